@@ -0,0 +1,87 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// manifestFile is the name of the build cache written in dst.
+const manifestFile = ".gendoc-manifest"
+
+// manifest records the hash of the source of each generated file.
+type manifest struct {
+	mu    sync.Mutex
+	Files map[string]string `json:"files"`
+}
+
+// readManifest reads the manifest in dst.
+// An empty manifest is returned if it doesn't exist or is broken.
+func readManifest(dst string) *manifest {
+	m := &manifest{Files: map[string]string{}}
+	p, err := ioutil.ReadFile(filepath.Join(dst, manifestFile))
+	if err != nil {
+		return m
+	}
+	if err := json.Unmarshal(p, m); err != nil || m.Files == nil {
+		m.Files = map[string]string{}
+	}
+	return m
+}
+
+func (m *manifest) write(dst string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	p, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dst, manifestFile), p, filePerm)
+}
+
+// fresh returns true if file was generated from a source with hash.
+func (m *manifest) fresh(file, hash string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.Files[file]
+	return ok && h == hash
+}
+
+func (m *manifest) set(file, hash string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.Files[file] = hash
+}
+
+// fileHash returns the hash of salt and the content of path.
+func fileHash(path, salt string) (string, error) {
+	p, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	h.Write([]byte(salt))
+	h.Write(p)
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// treeHash returns the hash of the files in paths.
+// A dereferenced file depends on the other files, so it is used as the salt.
+func treeHash(paths []string) (string, error) {
+	sorted := append([]string{}, paths...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	for _, path := range sorted {
+		fh, err := fileHash(path, path)
+		if err != nil {
+			return "", err
+		}
+		h.Write([]byte(fh))
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
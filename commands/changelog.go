@@ -0,0 +1,161 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	htmltemplate "html/template"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// GenerateChangelog renders the changes of the resources under src from
+// git revision from to revision to. format is "html" or "markdown".
+func GenerateChangelog(from, to, src, format string) error {
+	if from == "" || to == "" {
+		return fmt.Errorf("from and to must be specified")
+	}
+	changes, err := diffRevisions(from, to, src)
+	if err != nil {
+		return err
+	}
+	w, err := renderChangelog(from, to, changes, format)
+	if err != nil {
+		return err
+	}
+	w.WriteTo(os.Stdout)
+	return nil
+}
+
+// diffRevisions returns the changes of the resources under src between
+// two git revisions.
+func diffRevisions(from, to, src string) ([]schema.Change, error) {
+	oldSrc, err := exportRevision(from, src)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(oldSrc)
+
+	newSrc, err := exportRevision(to, src)
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(newSrc)
+
+	return DiffSchemaTree(oldSrc, newSrc)
+}
+
+// exportRevision writes the files under src at git revision rev to a
+// temporary directory, and returns it.
+func exportRevision(rev, src string) (string, error) {
+	if err := isDir(src); err != nil {
+		return "", err
+	}
+	out, err := git(src, "ls-tree", "-r", "--name-only", rev, ".")
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := ioutil.TempDir("", "gendoc")
+	if err != nil {
+		return "", err
+	}
+	for _, name := range strings.Split(strings.TrimSpace(string(out)), "\n") {
+		if name == "" || !isSchemaFile(name) {
+			continue
+		}
+		p, err := git(src, "show", rev+":./"+name)
+		if err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+		dst := filepath.Join(dir, name)
+		if err := createIfNotExist(filepath.Dir(dst)); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+		if err := ioutil.WriteFile(dst, p, filePerm); err != nil {
+			os.RemoveAll(dir)
+			return "", err
+		}
+	}
+	return dir, nil
+}
+
+// git runs the git command in dir.
+func git(dir string, args ...string) ([]byte, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	stderr := bytes.NewBuffer([]byte{})
+	cmd.Stderr = stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %v: %v %v", strings.Join(args, " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+type changelogParam struct {
+	From     string
+	To       string
+	Breaking []schema.Change
+	Others   []schema.Change
+}
+
+func renderChangelog(from, to string, changes []schema.Change, format string) (*bytes.Buffer, error) {
+	p := changelogParam{From: from, To: to}
+	for _, c := range changes {
+		if c.Breaking {
+			p.Breaking = append(p.Breaking, c)
+		} else {
+			p.Others = append(p.Others, c)
+		}
+	}
+
+	w := bytes.NewBuffer([]byte{})
+	var err error
+	switch format {
+	case "html":
+		tmpl := htmltemplate.Must(htmltemplate.New("changelog").Parse(changelogHTMLTmpl))
+		err = tmpl.Execute(w, p)
+	case "markdown", "md":
+		tmpl := template.Must(template.New("changelog").Parse(changelogMarkdownTmpl))
+		err = tmpl.Execute(w, p)
+	default:
+		err = fmt.Errorf("unknown format: %v", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+const changelogHTMLTmpl = `<h1 class="page-header">Changes from {{ .From }} to {{ .To }}</h1>
+{{ if .Breaking }}<h2>Breaking changes</h2>
+<ul>
+{{ range .Breaking }}  <li><strong>{{ .Resource }}</strong>{{ if .Link }} <code>{{ .Link }}</code>{{ end }}: {{ .Message }}</li>
+{{ end }}</ul>
+{{ end }}{{ if .Others }}<h2>Other changes</h2>
+<ul>
+{{ range .Others }}  <li><strong>{{ .Resource }}</strong>{{ if .Link }} <code>{{ .Link }}</code>{{ end }}: {{ .Message }}</li>
+{{ end }}</ul>
+{{ end }}{{ if not (or .Breaking .Others) }}<p>No changes.</p>
+{{ end }}`
+
+const changelogMarkdownTmpl = `## Changes from {{ .From }} to {{ .To }}
+{{ if .Breaking }}
+### Breaking changes
+
+{{ range .Breaking }}* **{{ .Resource }}**{{ if .Link }} ` + "`{{ .Link }}`" + `{{ end }}: {{ .Message }}
+{{ end }}{{ end }}{{ if .Others }}
+### Other changes
+
+{{ range .Others }}* **{{ .Resource }}**{{ if .Link }} ` + "`{{ .Link }}`" + `{{ end }}: {{ .Message }}
+{{ end }}{{ end }}{{ if not (or .Breaking .Others) }}
+No changes.
+{{ end }}`
@@ -0,0 +1,68 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestChangelog(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+	repo, err := ioutil.TempDir("", "repo")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(repo)
+	src := path.Join(repo, "src")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	commit := func(msg string) {
+		if _, err := git(repo, "add", "-A"); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := git(repo, "-c", "user.name=gendoc", "-c", "user.email=gendoc@example.com", "commit", "-q", "-m", msg); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := git(repo, "init", "-q"); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), renderScaffold("user").Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit("v1")
+	if _, err := git(repo, "tag", "v1"); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(path.Join(src, "user.yml"))
+	if err := ioutil.WriteFile(path.Join(src, "article.yml"), renderScaffold("article").Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	commit("v2")
+
+	changes, err := diffRevisions("v1", "HEAD", src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, err := renderChangelog("v1", "HEAD", changes, "markdown")
+	if err != nil {
+		t.Fatal(err)
+	}
+	md := w.String()
+	for _, s := range []string{
+		"## Changes from v1 to HEAD",
+		"### Breaking changes\n\n* **user**: resource removed",
+		"### Other changes\n\n* **article**: resource added",
+	} {
+		if !strings.Contains(md, s) {
+			t.Errorf("%q is not contained in %v", s, md)
+		}
+	}
+}
@@ -0,0 +1,65 @@
+package commands
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/ghodss/yaml"
+)
+
+// ConfigFile is the name of the project config, which is read from the
+// working directory.
+const ConfigFile = "gendoc.yml"
+
+// Config is the project config. It has the defaults of the flags of the
+// commands, and the flags take precedence over it.
+type Config struct {
+	Src      string `json:"src"`
+	Dst      string `json:"dst"`
+	Template string `json:"template"`
+	Overview string `json:"overview"`
+	// Pages is the directory of the markdown pages of the document.
+	Pages string `json:"pages"`
+	// Format is the output format of gen, "json" or "yaml".
+	Format string `json:"format"`
+	// Meta is the meta of the API, or nil if the config has no meta.
+	Meta *Meta `json:"meta"`
+	// Lint is the names of the lint rules checked by valid.
+	Lint []string `json:"lint"`
+}
+
+// ReadConfig reads the project config at path. An empty config is
+// returned if the file does not exist.
+func ReadConfig(path string) (Config, error) {
+	config := Config{}
+	p, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return config, err
+	}
+	if err := yaml.Unmarshal(p, &config); err != nil {
+		return config, fmt.Errorf("%v: %v", path, err)
+	}
+
+	if config.Meta != nil {
+		meta := newMeta()
+		if err := yaml.Unmarshal(p, &struct {
+			Meta *Meta `json:"meta"`
+		}{&meta}); err != nil {
+			return config, fmt.Errorf("%v: %v", path, err)
+		}
+		if err := meta.init(); err != nil {
+			return config, fmt.Errorf("%v: %v", path, err)
+		}
+		config.Meta = &meta
+	}
+	for _, name := range config.Lint {
+		if _, ok := lintRules[name]; !ok {
+			return config, fmt.Errorf("%v: unknown lint rule %v", path, name)
+		}
+	}
+	return config, nil
+}
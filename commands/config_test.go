@@ -0,0 +1,103 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+)
+
+func TestReadConfig(t *testing.T) {
+	dir, err := ioutil.TempDir("", "config")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := path.Join(dir, ConfigFile)
+	config, err := ReadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Src != "" || config.Meta != nil {
+		t.Errorf("missing config must be empty, but %v", config)
+	}
+
+	data := `src: ./src
+dst: ./dst
+format: yaml
+meta:
+  title: Example API
+  headers:
+  - "Accept: application/json"
+lint:
+- link-title
+`
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	config, err = ReadConfig(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Src != "./src" || config.Dst != "./dst" || config.Format != "yaml" {
+		t.Errorf("unexpected config %v", config)
+	}
+	if config.Meta == nil || config.Meta.Title != "Example API" || config.Meta.BaseURL != "http://localhost" || len(config.Meta.Headers) != 1 {
+		t.Errorf("unexpected meta %v", config.Meta)
+	}
+	if len(config.Lint) != 1 || config.Lint[0] != "link-title" {
+		t.Errorf("unexpected lint %v", config.Lint)
+	}
+
+	if err := ioutil.WriteFile(file, []byte("lint: [unknown]\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadConfig(file); err == nil {
+		t.Errorf("unknown lint rule must be an error")
+	}
+}
+
+func TestLintSchemaTree(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	data := `id: lint-user
+title: User
+properties:
+  id:
+    type: string
+    description: id of the user
+  name:
+    type: string
+links:
+- title: List
+  href: /users
+  method: GET
+- href: /users
+  method: POST
+`
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := LintSchemaTree(src, []string{"resource-title", "link-title", "property-description"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := path.Join(src, "user.yml")
+	expected := []string{
+		file + ":13:3: link POST /users has no title (link-title)",
+		file + ":7:3: property name has no description (property-description)",
+	}
+	if len(problems) != len(expected) {
+		t.Fatalf("expected %v, but %v", expected, problems)
+	}
+	for i := range expected {
+		if problems[i] != expected[i] {
+			t.Errorf("expected %v, but %v", expected[i], problems[i])
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// DiffSchemaTree returns the changes of the resources from oldSrc to newSrc.
+func DiffSchemaTree(oldSrc, newSrc string) ([]schema.Change, error) {
+	old, err := readAPIShape(oldSrc)
+	if err != nil {
+		return nil, fmt.Errorf("old: %v", err)
+	}
+	new, err := readAPIShape(newSrc)
+	if err != nil {
+		return nil, fmt.Errorf("new: %v", err)
+	}
+	return schema.DiffAPI(old, new), nil
+}
+
+// readAPIShape reads the resources under src and resolves them before
+// the other tree is loaded.
+func readAPIShape(src string) (schema.APIShape, error) {
+	if err := isDir(src); err != nil {
+		return nil, err
+	}
+	resources, err := readResources(src)
+	if err != nil {
+		return nil, err
+	}
+	// the resources are compared by id, so a resource without an id or
+	// with the id of another would hide the changes of the other.
+	errs := schema.ErrorList{}
+	files := map[string]schema.Position{}
+	for i := range resources {
+		r := &resources[i]
+		if r.Id == "" {
+			errs = append(errs, &schema.Error{Pos: r.Position(), Message: "resource has no id"})
+		} else if pos, ok := files[r.Id]; ok {
+			errs = append(errs, &schema.Error{Pos: r.Position(), Message: fmt.Sprintf("id %v is also used in %v", r.Id, pos)})
+		} else {
+			files[r.Id] = r.Position()
+		}
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return schema.NewAPIShape(resources), nil
+}
@@ -0,0 +1,33 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestDiffSchemaTreeWithoutId(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	for _, name := range []string{"user.yml", "article.yml"} {
+		data := "title: " + name + "\nproperties:\n  name:\n    type: string\n"
+		if err := ioutil.WriteFile(path.Join(src, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_, err = DiffSchemaTree(src, src)
+	if err == nil {
+		t.Fatal("resources without id must be an error")
+	}
+	for _, name := range []string{"user.yml", "article.yml"} {
+		if !strings.Contains(err.Error(), path.Join(src, name)+":1:1: resource has no id") {
+			t.Errorf("%v is not reported in %v", name, err)
+		}
+	}
+}
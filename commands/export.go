@@ -0,0 +1,235 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+const postmanSchema = "https://schema.getpostman.com/json/collection/v2.1.0/collection.json"
+
+// ExportCollection writes the links of the resources under src as a
+// collection of an API client. format is "postman".
+func ExportCollection(src string, meta Meta, format string) error {
+	if err := isDir(src); err != nil {
+		return err
+	}
+	if format != "postman" {
+		return fmt.Errorf("unknown format: %v", format)
+	}
+	resources, err := readResources(src)
+	if err != nil {
+		return err
+	}
+	if meta, err = meta.WithEnv(""); err != nil {
+		return err
+	}
+	p, err := json.MarshalIndent(postmanCollection(resources, meta), "", "  ")
+	if err != nil {
+		return err
+	}
+	os.Stdout.Write(append(p, '\n'))
+	return nil
+}
+
+// postman is the Postman Collection v2.1 format.
+type postman struct {
+	Info     postmanInfo       `json:"info"`
+	Item     []postmanItem     `json:"item"`
+	Variable []postmanKeyValue `json:"variable"`
+}
+
+type postmanInfo struct {
+	Name   string `json:"name"`
+	Schema string `json:"schema"`
+}
+
+// postmanItem is a folder if Item is not empty, or a request.
+type postmanItem struct {
+	Name        string            `json:"name"`
+	Description string            `json:"description,omitempty"`
+	Item        []postmanItem     `json:"item,omitempty"`
+	Request     *postmanRequest   `json:"request,omitempty"`
+	Response    []postmanResponse `json:"response,omitempty"`
+}
+
+// postmanResponse is an example response of a request.
+type postmanResponse struct {
+	Name   string            `json:"name"`
+	Code   int               `json:"code"`
+	Status string            `json:"status"`
+	Header []postmanKeyValue `json:"header"`
+	Body   string            `json:"body,omitempty"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanKeyValue `json:"header"`
+	Body   *postmanBody      `json:"body,omitempty"`
+	URL    postmanURL        `json:"url"`
+}
+
+type postmanBody struct {
+	Mode       string            `json:"mode"`
+	Raw        string            `json:"raw,omitempty"`
+	URLEncoded []postmanKeyValue `json:"urlencoded,omitempty"`
+	FormData   []postmanKeyValue `json:"formdata,omitempty"`
+	Options    interface{}       `json:"options,omitempty"`
+}
+
+type postmanURL struct {
+	Raw      string            `json:"raw"`
+	Host     []string          `json:"host"`
+	Path     []string          `json:"path"`
+	Query    []postmanKeyValue `json:"query,omitempty"`
+	Variable []postmanKeyValue `json:"variable,omitempty"`
+}
+
+type postmanKeyValue struct {
+	Key   string `json:"key"`
+	Value string `json:"value"`
+	Type  string `json:"type,omitempty"`
+}
+
+func postmanCollection(resources schema.SchemaSlice, meta Meta) postman {
+	c := postman{
+		Info:     postmanInfo{Name: meta.Title, Schema: postmanSchema},
+		Item:     []postmanItem{},
+		Variable: []postmanKeyValue{{Key: "baseUrl", Value: meta.BaseURL}},
+	}
+	for i := range resources {
+		r := &resources[i]
+		if len(r.Links) == 0 {
+			continue
+		}
+		folder := postmanItem{Name: r.Title, Description: r.Description}
+		if folder.Name == "" {
+			folder.Name = r.Id
+		}
+		for _, l := range r.Links {
+			folder.Item = append(folder.Item, postmanLink(r, l, meta))
+		}
+		c.Item = append(c.Item, folder)
+	}
+	return c
+}
+
+func postmanLink(r *schema.Schema, l *schema.LinkDescription, meta Meta) postmanItem {
+	method := strings.ToUpper(l.Method)
+	if method == "" {
+		method = "GET"
+	}
+	req := &postmanRequest{Method: method, Header: []postmanKeyValue{}}
+	example := meta.snippetRequest(l)
+	for _, h := range example.Header {
+		req.Header = append(req.Header, postmanKeyValue{Key: h.Name, Value: h.Value})
+	}
+
+	// the variables of href are the path variables such as ":id".
+	// a repeated name is numbered, as postman has a value per name.
+	path := ""
+	used := map[string]bool{}
+	for _, part := range l.HrefParts() {
+		if part.Param == "" {
+			path += part.Literal
+			continue
+		}
+		name := uniqueName(part.Param, used)
+		path += ":" + name
+		req.URL.Variable = append(req.URL.Variable, postmanKeyValue{Key: name})
+	}
+	req.URL.Host = []string{"{{baseUrl}}"}
+	req.URL.Path = []string{}
+	for _, seg := range strings.Split(strings.Trim(path, "/"), "/") {
+		if seg != "" {
+			req.URL.Path = append(req.URL.Path, seg)
+		}
+	}
+	req.URL.Raw = "{{baseUrl}}" + path
+
+	// a link without schema has the resource as its schema.
+	hasSchema := l.Schema != nil && l.Schema.CurrentRef != r.CurrentRef
+	for _, p := range example.Query {
+		req.URL.Query = append(req.URL.Query, postmanKeyValue{Key: p.Name, Value: p.Value})
+	}
+	if len(req.URL.Query) > 0 {
+		req.URL.Raw = "{{baseUrl}}" + path + "?" + example.RawQuery()
+	}
+	switch {
+	case method == "GET" || method == "HEAD" || method == "OPTIONS":
+	case method == "DELETE" && !hasSchema:
+	case l.EncType == "application/x-www-form-urlencoded":
+		req.Body = &postmanBody{Mode: "urlencoded", URLEncoded: exampleValues(l.Schema)}
+	case l.EncType == "multipart/form-data":
+		values := exampleValues(l.Schema)
+		for i := range values {
+			values[i].Type = "text"
+		}
+		req.Body = &postmanBody{Mode: "formdata", FormData: values}
+	default:
+		req.Body = &postmanBody{
+			Mode:    "raw",
+			Raw:     l.Schema.ExampleJSON(),
+			Options: map[string]interface{}{"raw": map[string]string{"language": "json"}},
+		}
+		encType := l.EncType
+		if encType == "" {
+			encType = "application/json"
+		}
+		req.Header = append(req.Header, postmanKeyValue{Key: "Content-Type", Value: encType})
+	}
+
+	name := l.Title
+	if name == "" {
+		name = method + " " + l.Href
+	}
+	item := postmanItem{Name: name, Description: l.Description, Request: req}
+	for _, res := range l.Responses() {
+		r := postmanResponse{
+			Name:   res.StatusLine(),
+			Code:   res.StatusCode,
+			Status: http.StatusText(res.StatusCode),
+			Header: []postmanKeyValue{},
+		}
+		if res.Description != "" {
+			r.Name = r.Name + " - " + res.Description
+		}
+		if res.Schema != nil {
+			r.Header = append(r.Header, postmanKeyValue{Key: "Content-Type", Value: "application/json"})
+			r.Body = res.Schema.ExampleJSON()
+		}
+		if res.StatusCode < 300 {
+			for _, h := range l.ResponseHeaders {
+				r.Header = append(r.Header, postmanKeyValue{Key: h.Name, Value: h.Example})
+			}
+		}
+		item.Response = append(item.Response, r)
+	}
+	return item
+}
+
+// exampleValues returns the example of s as key value pairs.
+func exampleValues(s *schema.Schema) []postmanKeyValue {
+	values := []postmanKeyValue{}
+	if s == nil {
+		return values
+	}
+	for _, kv := range s.ExampleGetData() {
+		if kv == "" {
+			continue
+		}
+		pair := strings.SplitN(kv, "=", 2)
+		k, _ := url.QueryUnescape(pair[0])
+		v := ""
+		if len(pair) == 2 {
+			v, _ = url.QueryUnescape(pair[1])
+		}
+		values = append(values, postmanKeyValue{Key: k, Value: v})
+	}
+	return values
+}
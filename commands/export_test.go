@@ -0,0 +1,77 @@
+package commands
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+func TestPostmanCollection(t *testing.T) {
+	user := `{
+  "id": "user",
+  "title": "User",
+  "properties": {"id": {"type": "integer", "example": 1}},
+  "links": [
+    {"title": "Search", "href": "/users", "method": "GET",
+     "schema": {"properties": {"q": {"type": "string", "example": "gopher"}}}},
+    {"title": "Update", "href": "/users/{(%23%2Fdefinitions%2Fid)}", "method": "PATCH", "encType": "application/json",
+     "statusCode": 200,
+     "errors": [{"status": 404, "description": "not found", "schema": {"properties": {"message": {"type": "string", "example": "no user"}}}}]}
+  ]
+}`
+	s, err := schema.NewSchemaFromBytes([]byte(user), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := Meta{Title: "API", BaseURL: "https://api.example.com", Headers: []string{"X-Token: AAA"}}
+	p, err := json.Marshal(postmanCollection(schema.SchemaSlice{*s}, meta))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, s := range []string{
+		`"schema":"https://schema.getpostman.com/json/collection/v2.1.0/collection.json"`,
+		`"variable":[{"key":"baseUrl","value":"https://api.example.com"}]`,
+		`{"name":"User","item":[{"name":"Search"`,
+		`"raw":"{{baseUrl}}/users?q=gopher"`,
+		`"query":[{"key":"q","value":"gopher"}]`,
+		`{"key":"X-Token","value":"AAA"}`,
+		`"raw":"{{baseUrl}}/users/:id","host":["{{baseUrl}}"],"path":["users",":id"]`,
+		`"body":{"mode":"raw","raw":"{\n  \"id\": 1\n}"`,
+		`"response":[{"name":"200 OK","code":200,"status":"OK","header":[{"key":"Content-Type","value":"application/json"}],"body":"{\n  \"id\": 1\n}"}`,
+		`{"name":"404 Not Found - not found","code":404,"status":"Not Found","header":[{"key":"Content-Type","value":"application/json"}],"body":"{\n  \"message\": \"no user\"\n}"}`,
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
+
+func TestPostmanCollectionParams(t *testing.T) {
+	user := `{
+  "id": "postman-params-user",
+  "definitions": {"id": {"type": "integer"}},
+  "links": [
+    {"title": "Article", "href": "/users/{(%23%2Fdefinitions%2Fid)}/articles/{(%23%2Fdefinitions%2Fid)}", "method": "GET"}
+  ]
+}`
+	s, err := schema.NewSchemaFromBytes([]byte(user), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := json.Marshal(postmanCollection(schema.SchemaSlice{*s}, Meta{}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`"raw":"{{baseUrl}}/users/:id/articles/:id2"`,
+		`"path":["users",":id","articles",":id2"]`,
+		`"variable":[{"key":"id","value":""},{"key":"id2","value":""}]`,
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
@@ -1,10 +1,10 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"io/ioutil"
 	"os"
-	"path"
 	"path/filepath"
 
 	"github.com/hiroosak/gendoc/schema"
@@ -15,24 +15,65 @@ const (
 	filePerm = 0644
 )
 
-// yaml2JSON creates json schema file from yaml file.
-func yaml2JSON(src, dst string, info os.FileInfo) error {
-	ext := filepath.Ext(src)
-	if ext != ".yaml" && ext != ".yml" {
-		return nil
+// yaml2JSON creates json schema file dst from yaml file src.
+// If resources is not nil, every resolvable $ref is inlined.
+func yaml2JSON(src, dst string, info os.FileInfo, resources map[string]*schema.Schema) error {
+	d, err := readSchemaFile(src, info, resources)
+	if err != nil {
+		return err
 	}
-	j, err := schema.YamlFileToJson(src, info)
+	j, err := json.MarshalIndent(d, "", "  ")
 	if err != nil {
 		return err
 	}
+	return ioutil.WriteFile(dst, j, filePerm)
+}
 
-	base := filepath.Base(src)
-	dstFile := base[0:len(base)-len(ext)] + ".json"
-	dstPath := path.Join(dst, dstFile)
+// json2YAML creates yaml file dst from json schema file src.
+// If resources is not nil, every resolvable $ref is inlined.
+func json2YAML(src, dst string, info os.FileInfo, resources map[string]*schema.Schema) error {
+	d, err := readSchemaFile(src, info, resources)
+	if err != nil {
+		return err
+	}
+	y, err := schema.FormatYAML(d)
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(dst, y, filePerm)
+}
 
-	ioutil.WriteFile(dstPath, j, filePerm)
+// readSchemaFile reads yaml or json schema file.
+// If src is in resources, the dereferenced schema is returned.
+func readSchemaFile(src string, info os.FileInfo, resources map[string]*schema.Schema) (map[string]interface{}, error) {
+	if s, ok := resources[src]; ok {
+		return s.Dereference(), nil
+	}
+	return schema.ReadFile(src, info)
+}
 
-	return nil
+// loadResources parses every schema file under src, keyed by path. A file
+// which can not be parsed is an error.
+func loadResources(src string) (map[string]*schema.Schema, error) {
+	resources := map[string]*schema.Schema{}
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() || !isSchemaFile(path) {
+			return nil
+		}
+		r, err := schema.NewSchemaFromFile(path, info)
+		if err != nil {
+			return err
+		}
+		resources[path] = r
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resources, nil
 }
 
 // isDir returns true if path is a directory.
@@ -51,8 +92,7 @@ func isDir(path string) error {
 func createIfNotExist(path string) error {
 	err := isDir(path)
 	if os.IsNotExist(err) {
-		os.MkdirAll(path, dirPerm)
-		return nil
+		return os.MkdirAll(path, dirPerm)
 	}
 	return err
 }
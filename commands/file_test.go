@@ -3,6 +3,8 @@ package commands
 import (
 	"io/ioutil"
 	"os"
+	"path"
+	"strings"
 	"testing"
 )
 
@@ -20,3 +22,37 @@ func TestCreateIfNotExist(t *testing.T) {
 		t.Fatal(err.Error())
 	}
 }
+
+func TestLoadResources(t *testing.T) {
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"load-ok.json": `{"id": "load-ok", "type": "object"}`,
+		"README.md":    "# not a schema",
+	}
+	for name, body := range files {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err.Error())
+		}
+	}
+	resources, err := loadResources(dir)
+	if err != nil {
+		t.Fatal(err.Error())
+	}
+	if len(resources) != 1 {
+		t.Errorf("only the schema file must be loaded. %v", resources)
+	}
+
+	broken := path.Join(dir, "load-broken.json")
+	if err := ioutil.WriteFile(broken, []byte(`{"id": `), 0644); err != nil {
+		t.Fatal(err.Error())
+	}
+	_, err = loadResources(dir)
+	if err == nil || !strings.Contains(err.Error(), broken) {
+		t.Errorf("the broken file must be reported. %v", err)
+	}
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// FormatTree rewrites the yaml and json files under src in the canonical
+// form, and returns the files which are changed.
+// If check is true, files are not rewritten.
+func FormatTree(src string, check bool) ([]string, error) {
+	if err := isDir(src); err != nil {
+		return nil, fmt.Errorf("src is not directory")
+	}
+
+	changed := []string{}
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if info.IsDir() {
+			return nil
+		}
+		ext := filepath.Ext(path)
+		if ext != ".json" && ext != ".yaml" && ext != ".yml" {
+			return nil
+		}
+		ok, err := formatFile(path, info, check)
+		if err != nil {
+			return fmt.Errorf("%v: %v", path, err)
+		}
+		if ok {
+			changed = append(changed, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return changed, nil
+}
+
+// formatFile returns true if the file is not in the canonical form.
+func formatFile(path string, info os.FileInfo, check bool) (bool, error) {
+	src, err := ioutil.ReadFile(path)
+	if err != nil {
+		return false, err
+	}
+
+	var out []byte
+	if filepath.Ext(path) == ".json" {
+		out, err = schema.FormatJSONSource(src)
+	} else {
+		// the comments of yaml are kept.
+		out, err = schema.FormatYAMLSource(src)
+	}
+	if err != nil {
+		return false, err
+	}
+
+	if bytes.Equal(src, out) {
+		return false, nil
+	}
+	if check {
+		return true, nil
+	}
+	return true, ioutil.WriteFile(path, out, info.Mode())
+}
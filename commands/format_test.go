@@ -0,0 +1,167 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestFormatTree(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	srcfile := path.Join(src, "user.yml")
+	w := renderScaffold("user")
+	if err := ioutil.WriteFile(srcfile, w.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	files, err := FormatTree(src, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 1 || files[0] != srcfile {
+		t.Fatalf("expected %v is listed. but %v", srcfile, files)
+	}
+	p, _ := ioutil.ReadFile(srcfile)
+	if string(p) != w.String() {
+		t.Errorf("file must not be rewritten in check mode")
+	}
+
+	if _, err := FormatTree(src, false); err != nil {
+		t.Fatal(err)
+	}
+	files, err = FormatTree(src, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("formatted file is listed. %v", files)
+	}
+}
+
+func TestFormatTreeKeepsComments(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	srcfile := path.Join(src, "user.yml")
+	data := `# user resource
+title: User
+id: format-comment-user
+properties:
+  # the name shown to others
+  name:
+    type: string # not empty
+links:
+- method: GET
+  href: /users
+`
+	if err := ioutil.WriteFile(srcfile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := FormatTree(src, false); err != nil {
+		t.Fatal(err)
+	}
+	p, _ := ioutil.ReadFile(srcfile)
+	for _, comment := range []string{"# user resource", "# the name shown to others", "# not empty"} {
+		if !strings.Contains(string(p), comment) {
+			t.Errorf("comment %q is removed in\n%s", comment, p)
+		}
+	}
+	if strings.Index(string(p), "id:") > strings.Index(string(p), "title:") {
+		t.Errorf("keys are not ordered in\n%s", p)
+	}
+	if strings.Index(string(p), "href:") > strings.Index(string(p), "method:") {
+		t.Errorf("link keys are not ordered in\n%s", p)
+	}
+
+	files, err := FormatTree(src, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(files) != 0 {
+		t.Errorf("formatted file is listed.\n%s", p)
+	}
+}
+
+func TestFormatTreeKeepsOrder(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	files := map[string]string{
+		"user.yml": `id: format-order-user
+definitions:
+  zeta:
+    type: string
+  id:
+    type: integer
+properties:
+  zeta:
+    $ref: "#/definitions/zeta"
+  id:
+    $ref: "#/definitions/id"
+  status:
+    type: string
+  created_at:
+    type: string
+`,
+		"article.json": `{
+  "id": "format-order-article",
+  "definitions": {"zeta": {"type": "string"}, "id": {"type": "integer"}},
+  "properties": {
+    "zeta": {"$ref": "#/definitions/zeta"},
+    "id": {"$ref": "#/definitions/id"},
+    "status": {"type": "string", "default": 1.5},
+    "created_at": {"type": "string"}
+  }
+}
+`,
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(path.Join(src, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := FormatTree(src, false); err != nil {
+		t.Fatal(err)
+	}
+	for name := range files {
+		p, _ := ioutil.ReadFile(path.Join(src, name))
+		props := string(p)[strings.Index(string(p), "properties"):]
+		last := -1
+		for _, key := range []string{"zeta", "id", "status", "created_at"} {
+			n := strings.Index(props, key)
+			if n < last {
+				t.Errorf("%v: properties are not in the source order\n%s", name, p)
+				break
+			}
+			last = n
+		}
+		defs := string(p)[strings.Index(string(p), "definitions"):]
+		if strings.Index(defs, "id") < strings.Index(defs, "zeta") {
+			t.Errorf("%v: definitions are not in the source order\n%s", name, p)
+		}
+		if strings.HasSuffix(name, ".json") && !strings.Contains(string(p), `"default": 1.5`) {
+			t.Errorf("%v: number is changed\n%s", name, p)
+		}
+	}
+
+	changed, err := FormatTree(src, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("formatted files are listed. %v", changed)
+	}
+}
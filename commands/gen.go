@@ -4,27 +4,295 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/hiroosak/gendoc/schema"
 )
 
-func GenerateJSON(src, dst string) error {
+// GenerateOption is the option of GenerateJSON and GenerateYAML.
+type GenerateOption struct {
+	// Deref inlines the references in the generated files.
+	Deref bool
+	// Clean removes the files generated by the previous runs whose source
+	// no longer exists.
+	Clean bool
+	// Jobs is the number of files converted in parallel.
+	// If it is less than 1, the number of CPUs is used.
+	Jobs int
+	// Files limits the conversion to these source files, which are
+	// converted even if they are not changed. All files are converted
+	// if it is empty.
+	Files []string
+}
+
+// GenerateSummary is the result of GenerateJSON and GenerateYAML.
+type GenerateSummary struct {
+	Converted int
+	Skipped   int
+	Failed    []error
+}
+
+func (s GenerateSummary) String() string {
+	return fmt.Sprintf("converted: %d, skipped: %d, failed: %d", s.Converted, s.Skipped, len(s.Failed))
+}
+
+type convertFunc func(src, dst string, info os.FileInfo, resources map[string]*schema.Schema) error
+
+type converter struct {
+	srcExts []string
+	dstExt  string
+	convert convertFunc
+}
+
+var (
+	jsonConverter = converter{
+		srcExts: []string{".yaml", ".yml"},
+		dstExt:  ".json",
+		convert: yaml2JSON,
+	}
+	yamlConverter = converter{
+		srcExts: []string{".json"},
+		dstExt:  ".yml",
+		convert: json2YAML,
+	}
+)
+
+// conversion is a source file and the file generated from it.
+type conversion struct {
+	src  string
+	dst  string
+	info os.FileInfo
+}
+
+// GenerateJSON converts the yaml files under src to json files in dst.
+// The directory structure of src is mirrored under dst, and files whose
+// source is not changed since the last run are skipped.
+func GenerateJSON(src, dst string, opt GenerateOption) (GenerateSummary, error) {
+	return generate(src, dst, opt, jsonConverter)
+}
+
+// GenerateYAML converts the json files under src to yaml files in dst.
+// The directory structure of src is mirrored under dst, and files whose
+// source is not changed since the last run are skipped.
+func GenerateYAML(src, dst string, opt GenerateOption) (GenerateSummary, error) {
+	return generate(src, dst, opt, yamlConverter)
+}
+
+func generate(src, dst string, opt GenerateOption, c converter) (GenerateSummary, error) {
+	var summary GenerateSummary
+
 	info, err := os.Lstat(src)
 	if err != nil {
-		return err
+		return summary, err
 	}
 	if !info.IsDir() {
-		return fmt.Errorf("src must be directory")
+		return summary, fmt.Errorf("src must be directory")
+	}
+	if opt.Clean && filepath.Clean(src) == filepath.Clean(dst) {
+		return summary, fmt.Errorf("clean can not be used when src and dst are the same directory")
 	}
 	if err := createIfNotExist(dst); err != nil {
-		return err
+		return summary, err
+	}
+
+	conversions, err := planConversions(src, dst, c)
+	if err != nil {
+		return summary, err
+	}
+
+	salt := c.dstExt
+	var resources map[string]*schema.Schema
+	if opt.Deref {
+		// load every resource so that references between files resolve.
+		if resources, err = loadResources(src); err != nil {
+			return summary, err
+		}
+		paths := []string{}
+		for path := range resources {
+			paths = append(paths, path)
+		}
+		tree, err := treeHash(paths)
+		if err != nil {
+			return summary, err
+		}
+		salt += ":deref:" + tree
+	}
+
+	cache := readManifest(dst)
+	previous := cache
+	next := &manifest{Files: map[string]string{}}
+	for file, hash := range cache.Files {
+		// the files generated by the other converter are kept.
+		if filepath.Ext(file) != c.dstExt {
+			next.Files[file] = hash
+		}
+	}
+	targets := conversions
+	if len(opt.Files) > 0 {
+		targets = filterConversions(conversions, opt.Files)
+		// the other files are kept as they are.
+		for file, hash := range cache.Files {
+			next.Files[file] = hash
+		}
+		cache = &manifest{Files: map[string]string{}}
+	}
+
+	jobs := opt.Jobs
+	if jobs < 1 {
+		jobs = runtime.NumCPU()
+	}
+	queue := make(chan conversion)
+	results := make(chan conversionResult)
+	var wg sync.WaitGroup
+	for i := 0; i < jobs; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for cv := range queue {
+				results <- convertFile(cv, dst, salt, cache, next, resources, c)
+			}
+		}()
 	}
+	go func() {
+		for _, cv := range targets {
+			queue <- cv
+		}
+		close(queue)
+		wg.Wait()
+		close(results)
+	}()
 
-	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+	for r := range results {
+		switch {
+		case r.err != nil:
+			summary.Failed = append(summary.Failed, r.err)
+		case r.skipped:
+			summary.Skipped++
+		default:
+			summary.Converted++
+		}
+	}
+
+	if len(summary.Failed) > 0 {
+		if err := next.write(dst); err != nil {
+			return summary, err
+		}
+		return summary, fmt.Errorf("%d files failed to convert", len(summary.Failed))
+	}
+	var cleanErr error
+	if opt.Clean {
+		cleanErr = cleanStale(dst, c.dstExt, previous, next, conversions)
+	}
+	if err := next.write(dst); err != nil {
+		return summary, err
+	}
+	return summary, cleanErr
+}
+
+type conversionResult struct {
+	skipped bool
+	err     error
+}
+
+// convertFile converts cv unless its source matches the hash in cache.
+// The hash of the converted or skipped file is recorded in next.
+func convertFile(cv conversion, dst, salt string, cache, next *manifest, resources map[string]*schema.Schema, c converter) conversionResult {
+	rel, err := filepath.Rel(dst, cv.dst)
+	if err != nil {
+		return conversionResult{err: err}
+	}
+	hash, err := fileHash(cv.src, salt)
+	if err != nil {
+		return conversionResult{err: fmt.Errorf("%v: %v", cv.src, err)}
+	}
+	if _, err := os.Stat(cv.dst); err == nil && cache.fresh(rel, hash) {
+		next.set(rel, hash)
+		return conversionResult{skipped: true}
+	}
+	if err := createIfNotExist(filepath.Dir(cv.dst)); err != nil {
+		return conversionResult{err: err}
+	}
+	if err := c.convert(cv.src, cv.dst, cv.info, resources); err != nil {
+		return conversionResult{err: fmt.Errorf("%v: %v", cv.src, err)}
+	}
+	next.set(rel, hash)
+	return conversionResult{}
+}
+
+// planConversions lists the files to convert, and returns an error if
+// two source files would be converted to the same file.
+func planConversions(src, dst string, c converter) ([]conversion, error) {
+	conversions := []conversion{}
+	sources := map[string]string{}
+
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
 		if info == nil || info.IsDir() {
 			return nil
 		}
-		if err := yaml2JSON(path, dst, info); err != nil {
+		ext := filepath.Ext(path)
+		if !hasExt(c.srcExts, ext) {
+			return nil
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
 			return err
 		}
+		dstPath := filepath.Join(dst, rel[0:len(rel)-len(ext)]+c.dstExt)
+		if s, ok := sources[dstPath]; ok {
+			return fmt.Errorf("%v and %v are both converted to %v", s, path, dstPath)
+		}
+		sources[dstPath] = path
+		conversions = append(conversions, conversion{src: path, dst: dstPath, info: info})
 		return nil
 	})
+	if err != nil {
+		return nil, err
+	}
+	return conversions, nil
+}
+
+// filterConversions returns the conversions whose source is in files.
+func filterConversions(conversions []conversion, files []string) []conversion {
+	srcs := map[string]bool{}
+	for _, f := range files {
+		srcs[filepath.Clean(f)] = true
+	}
+	rs := []conversion{}
+	for _, cv := range conversions {
+		if srcs[filepath.Clean(cv.src)] {
+			rs = append(rs, cv)
+		}
+	}
+	return rs
+}
+
+// cleanStale removes the files with ext which are recorded in the previous
+// manifest but are not generated from the current sources. The other files
+// in dst are not touched.
+func cleanStale(dst, ext string, previous, next *manifest, conversions []conversion) error {
+	generated := map[string]bool{}
+	for _, cv := range conversions {
+		generated[filepath.Clean(cv.dst)] = true
+	}
+	for file := range previous.Files {
+		path := filepath.Join(dst, file)
+		if filepath.Ext(file) != ext || generated[path] {
+			continue
+		}
+		if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		delete(next.Files, file)
+	}
+	return nil
+}
+
+func hasExt(exts []string, ext string) bool {
+	for _, e := range exts {
+		if e == ext {
+			return true
+		}
+	}
+	return false
 }
@@ -22,7 +22,7 @@ func TestGenerateJSON(t *testing.T) {
 	if err := ioutil.WriteFile(srcfile, w.Bytes(), 0755); err != nil {
 		t.Fatal(err)
 	}
-	if err := GenerateJSON(src, dst); err != nil {
+	if _, err := GenerateJSON(src, dst, GenerateOption{}); err != nil {
 		t.Error(err)
 	}
 	dstfile := path.Join(dst, "user.json")
@@ -33,3 +33,118 @@ func TestGenerateJSON(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestGenerateJSONTree(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dst)
+
+	w := renderScaffold("user")
+	for _, dir := range []string{"v1", "v2"} {
+		if err := os.Mkdir(path.Join(src, dir), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path.Join(src, dir, "user.yml"), w.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// v1/article.json is generated, and its source is removed.
+	article := path.Join(src, "v1", "article.yml")
+	if err := ioutil.WriteFile(article, renderScaffold("article").Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateJSON(src, dst, GenerateOption{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Remove(article); err != nil {
+		t.Fatal(err)
+	}
+	stale := path.Join(dst, "v1", "article.json")
+	// the files which are not generated by gen are kept.
+	unrelated := path.Join(dst, "v1", "settings.json")
+	if err := ioutil.WriteFile(unrelated, []byte("{}"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := GenerateJSON(src, dst, GenerateOption{Clean: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"v1/user.json", "v2/user.json", "v1/settings.json"} {
+		if _, err := os.Stat(path.Join(dst, f)); err != nil {
+			t.Error(err)
+		}
+	}
+	if _, err := os.Stat(stale); !os.IsNotExist(err) {
+		t.Errorf("%v is not removed", stale)
+	}
+	if m := readManifest(dst); m.Files["v1/article.json"] != "" {
+		t.Errorf("%v is left in the manifest", stale)
+	}
+
+	// v1/user.yml and v1/user.yaml collide.
+	if err := ioutil.WriteFile(path.Join(src, "v1", "user.yaml"), w.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateJSON(src, dst, GenerateOption{}); err == nil {
+		t.Error("collision is not detected")
+	}
+}
+
+func TestGenerateJSONCache(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dst)
+
+	for _, r := range []string{"user", "article"} {
+		w := renderScaffold(r)
+		if err := ioutil.WriteFile(path.Join(src, r+".yml"), w.Bytes(), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	opt := GenerateOption{Jobs: 2}
+	s, err := GenerateJSON(src, dst, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Converted != 2 || s.Skipped != 0 {
+		t.Errorf("expected 2 files are converted. %v", s)
+	}
+
+	w := renderScaffold("comment")
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), w.Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err = GenerateJSON(src, dst, opt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Converted != 1 || s.Skipped != 1 {
+		t.Errorf("expected only the changed file is converted. %v", s)
+	}
+
+	if err := ioutil.WriteFile(path.Join(src, "broken.yml"), []byte("a: b: c"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	s, err = GenerateJSON(src, dst, opt)
+	if err == nil {
+		t.Error("error is expected for broken file")
+	}
+	if len(s.Failed) != 1 || s.Skipped != 2 {
+		t.Errorf("expected 1 file fails and 2 files are skipped. %v", s)
+	}
+}
@@ -0,0 +1,341 @@
+package commands
+
+import (
+	"bytes"
+	"go/format"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// goMethod is a method of the generated client.
+type goMethod struct {
+	Name        string
+	Doc         []string
+	Method      string
+	Href        string
+	Path        string
+	Params      []string
+	EncType     string
+	RequestType string
+	// ResultType is the type decoded from the response, or empty if
+	// the response has no body.
+	ResultType string
+	// ResultSlice is true if the result is a slice of ResultType.
+	ResultSlice bool
+}
+
+// clientFile returns the go source of the client of the resources.
+func (g *goGenerator) clientFile(pkg string, meta Meta) ([]byte, error) {
+	methods := []goMethod{}
+	for _, r := range g.resources {
+		for _, l := range r.Links {
+			methods = append(methods, g.clientMethod(r, l))
+		}
+	}
+
+	p := struct {
+		Package string
+		Meta    Meta
+		Methods []goMethod
+	}{
+		Package: pkg,
+		Meta:    meta,
+		Methods: methods,
+	}
+
+	funcs := template.FuncMap{"quote": strconv.Quote}
+	tmpl := template.Must(template.New("client").Funcs(funcs).Parse(goClientTmpl))
+	w := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(w, p); err != nil {
+		return nil, err
+	}
+	return format.Source(w.Bytes())
+}
+
+func (g *goGenerator) clientMethod(r *schema.Schema, l *schema.LinkDescription) goMethod {
+	m := goMethod{
+		Name:    goName(r.Id) + goName(linkName(l)),
+		Method:  strings.ToUpper(l.Method),
+		Href:    l.Href,
+		EncType: l.EncType,
+	}
+	if m.Method == "" {
+		m.Method = "GET"
+	}
+	if m.EncType == "" {
+		m.EncType = "application/json"
+	}
+	if desc := strings.TrimSpace(l.Description); desc != "" {
+		m.Doc = strings.Split(desc, "\n")
+	}
+
+	path := []string{}
+	used := map[string]bool{}
+	for _, part := range l.HrefParts() {
+		if part.Param == "" {
+			path = append(path, strconv.Quote(part.Literal))
+			continue
+		}
+		param := uniqueName(goParamName(part.Param), used)
+		m.Params = append(m.Params, param)
+		path = append(path, "url.PathEscape("+param+")")
+	}
+	m.Path = strings.Join(path, " + ")
+	if m.Path == "" {
+		m.Path = `""`
+	}
+
+	if hasRequestSchema(r, l) {
+		m.RequestType = goRequestName(r, l)
+	}
+	switch {
+	// the response of HEAD has no body.
+	case l.Response().Schema == nil || m.Method == "HEAD":
+	case hasTargetSchema(r, l):
+		m.ResultType = goResponseName(r, l)
+	case l.Rel == "instances":
+		m.ResultType = g.names[r]
+		m.ResultSlice = true
+	default:
+		m.ResultType = g.names[r]
+	}
+	return m
+}
+
+// goKeywords are the reserved words which can not be parameter names.
+var goKeywords = map[string]bool{
+	"break": true, "case": true, "chan": true, "const": true, "continue": true,
+	"default": true, "defer": true, "else": true, "fallthrough": true, "for": true,
+	"func": true, "go": true, "goto": true, "if": true, "import": true,
+	"interface": true, "map": true, "package": true, "range": true, "return": true,
+	"select": true, "struct": true, "switch": true, "type": true, "var": true,
+	// names used in the generated methods.
+	"c": true, "ctx": true, "opts": true, "body": true, "result": true, "url": true,
+}
+
+// goParamName returns an unexported go identifier from s, such as "userID"
+// from "user_id" or "apiKey" from "api_key".
+func goParamName(s string) string {
+	name := goName(s)
+	n := 0
+	for n < len(name) && name[n] >= 'A' && name[n] <= 'Z' {
+		n++
+	}
+	switch {
+	case n == len(name):
+		name = strings.ToLower(name)
+	case n > 1:
+		// the last upper case letter begins the next word.
+		name = strings.ToLower(name[0:n-1]) + name[n-1:]
+	default:
+		name = strings.ToLower(name[0:1]) + name[1:]
+	}
+	if goKeywords[name] {
+		name = name + "Param"
+	}
+	return name
+}
+
+// uniqueName returns name, or name numbered such as "id2" if name is
+// already used. The returned name is added to used.
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for i := 2; used[unique]; i++ {
+		unique = name + strconv.Itoa(i)
+	}
+	used[unique] = true
+	return unique
+}
+
+const goClientTmpl = `// Code generated by gendoc. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime/multipart"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultBaseURL is the base url of the API.
+const DefaultBaseURL = {{ quote .Meta.BaseURL }}
+
+// Client is the client of the API.
+type Client struct {
+	// BaseURL is prepended to the path of each request.
+	BaseURL string
+	// Header is sent with each request.
+	Header http.Header
+	// HTTPClient sends the requests. http.DefaultClient is used if nil.
+	HTTPClient *http.Client
+}
+
+// NewClient returns a client with the base url and the headers of the API.
+func NewClient() *Client {
+	header := http.Header{}
+{{- range .Meta.Headers }}
+	addHeader(header, {{ quote . }})
+{{- end }}
+	return &Client{
+		BaseURL: DefaultBaseURL,
+		Header:  header,
+	}
+}
+
+// Error is returned when the API responds with an error status.
+type Error struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%v %v", e.StatusCode, strings.TrimSpace(string(e.Body)))
+}
+{{ range .Methods }}
+// {{ .Name }} calls {{ .Method }} {{ .Href }}.
+{{- range .Doc }}
+// {{ . }}
+{{- end }}
+func (c *Client) {{ .Name }}(ctx context.Context{{ range .Params }}, {{ . }} string{{ end }}{{ if .RequestType }}, opts *{{ .RequestType }}{{ end }}) {{ if .ResultType }}({{ if .ResultSlice }}[]{{ .ResultType }}{{ else }}*{{ .ResultType }}{{ end }}, error){{ else }}error{{ end }} {
+	var body interface{}
+{{- if .RequestType }}
+	if opts != nil {
+		body = opts
+	}
+{{- end }}
+{{- if .ResultType }}
+	var result {{ if .ResultSlice }}[]{{ end }}{{ .ResultType }}
+	if err := c.do(ctx, {{ quote .Method }}, {{ .Path }}, {{ quote .EncType }}, body, &result); err != nil {
+		return nil, err
+	}
+	return {{ if not .ResultSlice }}&{{ end }}result, nil
+{{- else }}
+	return c.do(ctx, {{ quote .Method }}, {{ .Path }}, {{ quote .EncType }}, body, nil)
+{{- end }}
+}
+{{ end }}
+func (c *Client) do(ctx context.Context, method, path, encType string, body interface{}, result interface{}) error {
+	u := c.BaseURL + path
+	var r io.Reader
+	contentType := ""
+	if body != nil {
+		switch {
+		case method == "GET" || method == "HEAD" || method == "DELETE":
+			q, err := values(body)
+			if err != nil {
+				return err
+			}
+			if len(q) > 0 {
+				u += "?" + q.Encode()
+			}
+		case encType == "application/x-www-form-urlencoded":
+			q, err := values(body)
+			if err != nil {
+				return err
+			}
+			r = strings.NewReader(q.Encode())
+			contentType = encType
+		case encType == "multipart/form-data":
+			q, err := values(body)
+			if err != nil {
+				return err
+			}
+			buf := &bytes.Buffer{}
+			w := multipart.NewWriter(buf)
+			for k, vs := range q {
+				for _, v := range vs {
+					w.WriteField(k, v)
+				}
+			}
+			if err := w.Close(); err != nil {
+				return err
+			}
+			r = buf
+			contentType = w.FormDataContentType()
+		default:
+			p, err := json.Marshal(body)
+			if err != nil {
+				return err
+			}
+			r = bytes.NewReader(p)
+			contentType = encType
+		}
+	}
+
+	req, err := http.NewRequest(method, u, r)
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(ctx)
+	for k, vs := range c.Header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+
+	client := c.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 300 {
+		p, _ := ioutil.ReadAll(res.Body)
+		return &Error{StatusCode: res.StatusCode, Body: p}
+	}
+	if result == nil || res.StatusCode == http.StatusNoContent || res.Request.Method == "HEAD" {
+		return nil
+	}
+	// an empty body is no result.
+	if err := json.NewDecoder(res.Body).Decode(result); err != nil && err != io.EOF {
+		return err
+	}
+	return nil
+}
+
+// values encodes the fields of body as url values.
+func values(body interface{}) (url.Values, error) {
+	p, err := json.Marshal(body)
+	if err != nil {
+		return nil, err
+	}
+	var m map[string]interface{}
+	if err := json.Unmarshal(p, &m); err != nil {
+		return nil, err
+	}
+	q := url.Values{}
+	for k, v := range m {
+		if vs, ok := v.([]interface{}); ok {
+			for _, v := range vs {
+				q.Add(k, fmt.Sprintf("%v", v))
+			}
+			continue
+		}
+		q.Set(k, fmt.Sprintf("%v", v))
+	}
+	return q, nil
+}
+
+func addHeader(header http.Header, line string) {
+	if n := strings.Index(line, ":"); n > 0 {
+		header.Add(strings.TrimSpace(line[0:n]), strings.TrimSpace(line[n+1:]))
+	}
+}
+`
@@ -0,0 +1,588 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// GenerateGo writes go types of the resources under src to out.
+// A file is written for each resource. If client is true, the client of
+// the links is written to client.go with the base url and headers of meta.
+func GenerateGo(src, pkg, out string, meta Meta, client bool) error {
+	if err := isDir(src); err != nil {
+		return err
+	}
+	if pkg == "" {
+		return fmt.Errorf("pkg must be specified")
+	}
+	if err := createIfNotExist(out); err != nil {
+		return err
+	}
+	g, err := newGoGenerator(src)
+	if err != nil {
+		return err
+	}
+	files, err := g.typesFiles(pkg)
+	if err != nil {
+		return err
+	}
+	if client {
+		p, err := g.clientFile(pkg, meta)
+		if err != nil {
+			return fmt.Errorf("client: %v", err)
+		}
+		if err := addFile(files, "client.go", p); err != nil {
+			return fmt.Errorf("client: %v", err)
+		}
+	}
+	if err := checkDecls(files); err != nil {
+		return err
+	}
+	// the files are written only if all of them are generated, so that
+	// out is not left with a package which does not compile.
+	return writeFiles(out, files)
+}
+
+// typesFiles returns the go source of the types of each resource by file
+// name.
+func (g *goGenerator) typesFiles(pkg string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	for _, r := range g.resources {
+		p, err := g.typesFile(pkg, r)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %v", r.Id, err)
+		}
+		if err := addFile(files, goFileName(r.Id)+".go", p); err != nil {
+			return nil, fmt.Errorf("%v: %v", r.Id, err)
+		}
+	}
+	return files, nil
+}
+
+// addFile adds file name to files, or returns an error if another file is
+// written to name.
+func addFile(files map[string][]byte, name string, p []byte) error {
+	if _, ok := files[name]; ok {
+		return fmt.Errorf("%v is written twice", name)
+	}
+	files[name] = p
+	return nil
+}
+
+// checkDecls returns an error if a name, or a method of a type, is
+// declared twice in the go files. format.Source accepts such files, but
+// the package does not compile.
+func checkDecls(files map[string][]byte) error {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	declared := map[string]string{}
+	for _, name := range names {
+		f, err := parser.ParseFile(token.NewFileSet(), name, files[name], 0)
+		if err != nil {
+			return err
+		}
+		for _, decl := range f.Decls {
+			for _, id := range declNames(decl) {
+				if other, ok := declared[id]; ok {
+					if other == name {
+						return fmt.Errorf("%v: %v is declared twice", name, id)
+					}
+					return fmt.Errorf("%v: %v is also declared in %v", name, id, other)
+				}
+				declared[id] = name
+			}
+		}
+	}
+	return nil
+}
+
+// declNames returns the names declared by decl. A method is named as
+// "Type.Method".
+func declNames(decl ast.Decl) []string {
+	names := []string{}
+	switch d := decl.(type) {
+	case *ast.GenDecl:
+		for _, spec := range d.Specs {
+			switch spec := spec.(type) {
+			case *ast.TypeSpec:
+				names = append(names, spec.Name.Name)
+				if iface, ok := spec.Type.(*ast.InterfaceType); ok {
+					for _, m := range iface.Methods.List {
+						for _, n := range m.Names {
+							names = append(names, spec.Name.Name+"."+n.Name)
+						}
+					}
+				}
+			case *ast.ValueSpec:
+				for _, n := range spec.Names {
+					if n.Name != "_" {
+						names = append(names, n.Name)
+					}
+				}
+			}
+		}
+	case *ast.FuncDecl:
+		if d.Recv == nil || len(d.Recv.List) == 0 {
+			if d.Name.Name != "init" {
+				names = append(names, d.Name.Name)
+			}
+			break
+		}
+		recv := d.Recv.List[0].Type
+		if star, ok := recv.(*ast.StarExpr); ok {
+			recv = star.X
+		}
+		if id, ok := recv.(*ast.Ident); ok {
+			names = append(names, id.Name+"."+d.Name.Name)
+		}
+	}
+	return names
+}
+
+// writeFiles writes files to dir by name.
+func writeFiles(dir string, files map[string][]byte) error {
+	names := []string{}
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := ioutil.WriteFile(filepath.Join(dir, name), files[name], filePerm); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// goGenerator maps the schemas to go types.
+type goGenerator struct {
+	resources []*schema.Schema
+	// names is the type name of the resources and definitions.
+	names map[*schema.Schema]string
+}
+
+// goType is a named type declaration.
+type goType struct {
+	name   string
+	doc    string
+	fields []goField
+	// underlying is the type of a non struct type.
+	underlying string
+	// alias is true if the type is an alias of underlying, so that
+	// time.Time keeps its json methods.
+	alias bool
+	enum  []interface{}
+}
+
+type goField struct {
+	name string
+	key  string
+	typ  string
+	doc  string
+}
+
+func newGoGenerator(src string) (*goGenerator, error) {
+	resources, err := loadResources(src)
+	if err != nil {
+		return nil, err
+	}
+	g := &goGenerator{names: map[*schema.Schema]string{}}
+	for _, r := range resources {
+		if r.Id == "" {
+			continue
+		}
+		g.resources = append(g.resources, r)
+	}
+	sort.Sort(schemasByID(g.resources))
+
+	for _, r := range g.resources {
+		name := goName(r.Id)
+		g.names[r] = name
+		for _, key := range r.DefinitionNames() {
+			g.names[r.Definitions[key]] = name + goName(key)
+		}
+	}
+	return g, nil
+}
+
+// resourceTypes returns the types of the resource r, its definitions and
+// the request and response of its links.
+func (g *goGenerator) resourceTypes(r *schema.Schema) []*goType {
+	types := []*goType{}
+	name := g.names[r]
+	types = g.declare(name, r, fmt.Sprintf("%v is the %v resource.", name, r.Id), types)
+
+	for _, key := range r.DefinitionNames() {
+		d := r.Definitions[key]
+		n := g.names[d]
+		types = g.declare(n, d, fmt.Sprintf("%v is the %v of %v.", n, key, r.Id), types)
+	}
+
+	for _, l := range r.Links {
+		if hasRequestSchema(r, l) {
+			n := goRequestName(r, l)
+			types = g.declare(n, l.Schema, fmt.Sprintf("%v is the request of %v %v.", n, l.Method, l.Href), types)
+		}
+		if hasTargetSchema(r, l) {
+			n := goResponseName(r, l)
+			types = g.declare(n, l.TargetSchema, fmt.Sprintf("%v is the response of %v %v.", n, l.Method, l.Href), types)
+		}
+	}
+	return types
+}
+
+// declare appends the type of s named name to types, and the types of its
+// inline objects and enums.
+func (g *goGenerator) declare(name string, s *schema.Schema, doc string, types []*goType) []*goType {
+	if a := s.Alias(); a != nil {
+		s = a
+	}
+	t := &goType{name: name, doc: doc}
+	if desc := strings.TrimSpace(s.Description); desc != "" {
+		t.doc = doc + "\n" + desc
+	}
+	types = append(types, t)
+
+	if n, ok := g.names[s]; ok && n != name {
+		t.underlying = n
+		t.alias = true
+		return types
+	}
+	if len(s.Enum) > 0 {
+		t.underlying = goEnumType(s)
+		t.enum = s.Enum
+		return types
+	}
+	if len(s.Properties) == 0 {
+		var inline []*goType
+		t.underlying, inline = g.inlineType(name, s, "")
+		t.alias = true
+		return append(types, inline...)
+	}
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	for _, key := range s.PropertyNames() {
+		p := s.Properties[key]
+		n := name + goName(key)
+		typ, inline := g.goType(n, p, fmt.Sprintf("%v is the %v of %v.", n, key, name))
+		types = append(types, inline...)
+
+		f := goField{name: goName(key), key: key, typ: typ}
+		if a := p.Alias(); a != nil {
+			f.doc = strings.TrimSpace(a.Description)
+		}
+		if !required[key] {
+			f.key = key + ",omitempty"
+			if isNilable(typ) {
+				f.typ = typ
+			} else {
+				f.typ = "*" + typ
+			}
+		}
+		t.fields = append(t.fields, f)
+	}
+	return types
+}
+
+// goType returns the go type of s, and the types declared inline.
+// name and doc are used for an inline type.
+func (g *goGenerator) goType(name string, s *schema.Schema, doc string) (string, []*goType) {
+	if n, ok := g.names[s]; ok {
+		return n, nil
+	}
+	a := s.Alias()
+	if a == nil {
+		return "interface{}", nil
+	}
+	if n, ok := g.names[a]; ok {
+		return n, nil
+	}
+	return g.inlineType(name, a, doc)
+}
+
+// inlineType returns the go type of s which is not named.
+func (g *goGenerator) inlineType(name string, s *schema.Schema, doc string) (string, []*goType) {
+	if len(s.Enum) > 0 || len(s.Properties) > 0 {
+		return name, g.declare(name, s, doc, nil)
+	}
+	switch schemaType(s) {
+	case "array":
+		if len(s.Items) == 0 {
+			return "[]interface{}", nil
+		}
+		n := name + "Item"
+		typ, inline := g.goType(n, s.Items[0], fmt.Sprintf("%v is the item of %v.", n, name))
+		return "[]" + typ, inline
+	case "object":
+		return "map[string]interface{}", nil
+	case "":
+		return "interface{}", nil
+	}
+	return goScalarType(s), nil
+}
+
+// typesFile returns the go source of the types of resource r.
+func (g *goGenerator) typesFile(pkg string, r *schema.Schema) ([]byte, error) {
+	types := g.resourceTypes(r)
+
+	body := bytes.NewBuffer([]byte{})
+	for _, t := range types {
+		writeGoType(body, t)
+	}
+
+	w := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(w, "// Code generated by gendoc. DO NOT EDIT.\n\npackage %v\n\n", pkg)
+	if strings.Contains(body.String(), "time.Time") {
+		fmt.Fprintf(w, "import \"time\"\n\n")
+	}
+	body.WriteTo(w)
+	return format.Source(w.Bytes())
+}
+
+func writeGoType(w *bytes.Buffer, t *goType) {
+	writeGoComment(w, "", t.doc)
+	if t.underlying != "" && t.alias {
+		fmt.Fprintf(w, "type %v = %v\n\n", t.name, t.underlying)
+		return
+	}
+	if t.underlying != "" {
+		fmt.Fprintf(w, "type %v %v\n\n", t.name, t.underlying)
+		if len(t.enum) > 0 {
+			fmt.Fprintf(w, "const (\n")
+			names := map[string]bool{}
+			for _, v := range t.enum {
+				lit, ok := goLiteral(t.underlying, v)
+				if !ok {
+					continue
+				}
+				name := t.name + goName(fmt.Sprintf("%v", v))
+				for i := 2; names[name]; i++ {
+					name = fmt.Sprintf("%v%v%d", t.name, goName(fmt.Sprintf("%v", v)), i)
+				}
+				names[name] = true
+				fmt.Fprintf(w, "%v %v = %v\n", name, t.name, lit)
+			}
+			fmt.Fprintf(w, ")\n\n")
+		}
+		return
+	}
+	fmt.Fprintf(w, "type %v struct {\n", t.name)
+	for _, f := range t.fields {
+		writeGoComment(w, "\t", f.doc)
+		fmt.Fprintf(w, "\t%v %v `json:\"%v\"`\n", f.name, f.typ, f.key)
+	}
+	fmt.Fprintf(w, "}\n\n")
+}
+
+func writeGoComment(w *bytes.Buffer, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(w, "%v// %v\n", indent, strings.TrimSpace(line))
+	}
+}
+
+// goScalarType returns the go type of a non object schema.
+func goScalarType(s *schema.Schema) string {
+	switch schemaType(s) {
+	case "string":
+		if s.Format == "date-time" {
+			return "time.Time"
+		}
+		return "string"
+	case "integer":
+		return "int64"
+	case "number":
+		return "float64"
+	case "boolean":
+		return "bool"
+	}
+	return "interface{}"
+}
+
+// goLiteral returns enum value v as a go literal of typ. A value which
+// typ can not have, such as null, is skipped.
+func goLiteral(typ string, v interface{}) (string, bool) {
+	switch typ {
+	case "string":
+		switch v.(type) {
+		case nil, map[string]interface{}, []interface{}:
+			return "", false
+		}
+		return strconv.Quote(fmt.Sprintf("%v", v)), true
+	case "int64":
+		if f, ok := v.(float64); ok && f == float64(int64(f)) {
+			return strconv.FormatInt(int64(f), 10), true
+		}
+	case "float64":
+		if f, ok := v.(float64); ok {
+			return strconv.FormatFloat(f, 'g', -1, 64), true
+		}
+	case "bool":
+		if b, ok := v.(bool); ok {
+			return strconv.FormatBool(b), true
+		}
+	}
+	return "", false
+}
+
+// goEnumType returns the go type of an enum, guessed from its values if
+// the type is not specified.
+func goEnumType(s *schema.Schema) string {
+	if typ := goScalarType(s); typ != "interface{}" && typ != "time.Time" {
+		return typ
+	}
+	for _, v := range s.Enum {
+		switch v.(type) {
+		case string:
+			return "string"
+		case float64:
+			return "float64"
+		case bool:
+			return "bool"
+		}
+	}
+	return "string"
+}
+
+// schemaType returns the type of s except "null", or empty string if s
+// has no type or several types.
+func schemaType(s *schema.Schema) string {
+	types := []string{}
+	for _, t := range s.Type {
+		if t != "null" {
+			types = append(types, t)
+		}
+	}
+	if len(types) != 1 {
+		if len(s.Properties) > 0 {
+			return "object"
+		}
+		return ""
+	}
+	return types[0]
+}
+
+func isNilable(typ string) bool {
+	return strings.HasPrefix(typ, "[]") || strings.HasPrefix(typ, "map[") || typ == "interface{}"
+}
+
+// hasRequestSchema returns true if link l of resource r has its own schema.
+func hasRequestSchema(r *schema.Schema, l *schema.LinkDescription) bool {
+	return l.Schema != nil && l.Schema != r
+}
+
+// hasTargetSchema returns true if link l of resource r has its own
+// targetSchema.
+func hasTargetSchema(r *schema.Schema, l *schema.LinkDescription) bool {
+	return l.TargetSchema != nil && l.TargetSchema != r
+}
+
+// goRequestName returns the type name of the schema of link l.
+func goRequestName(r *schema.Schema, l *schema.LinkDescription) string {
+	return goName(r.Id) + goName(linkName(l)) + "Request"
+}
+
+// goResponseName returns the type name of the targetSchema of link l.
+func goResponseName(r *schema.Schema, l *schema.LinkDescription) string {
+	return goName(r.Id) + goName(linkName(l)) + "Response"
+}
+
+// linkName returns the name of link l, used for go identifiers.
+func linkName(l *schema.LinkDescription) string {
+	if l.Title != "" {
+		return l.Title
+	}
+	if l.Rel != "" {
+		return l.Rel
+	}
+	return l.Method
+}
+
+// goInitialisms are the words written in upper case in go names.
+var goInitialisms = map[string]bool{
+	"API":  true,
+	"HTML": true,
+	"HTTP": true,
+	"ID":   true,
+	"JSON": true,
+	"URL":  true,
+	"UUID": true,
+}
+
+// goName returns an exported go identifier from s, such as "UserID" from
+// "user_id" or "userId".
+func goName(s string) string {
+	words := []string{}
+	word := []rune{}
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, string(word))
+			word = []rune{}
+		}
+	}
+	prev := rune(0)
+	for _, c := range s {
+		switch {
+		case !unicode.IsLetter(c) && !unicode.IsDigit(c):
+			flush()
+		case unicode.IsUpper(c) && unicode.IsLower(prev):
+			flush()
+			word = append(word, c)
+		default:
+			word = append(word, c)
+		}
+		prev = c
+	}
+	flush()
+
+	name := ""
+	for _, w := range words {
+		if upper := strings.ToUpper(w); goInitialisms[upper] {
+			name += upper
+			continue
+		}
+		rs := []rune(w)
+		name += string(unicode.ToUpper(rs[0])) + string(rs[1:])
+	}
+	if name == "" || unicode.IsDigit([]rune(name)[0]) {
+		name = "X" + name
+	}
+	return name
+}
+
+// goFileName returns the file name for resource id.
+func goFileName(id string) string {
+	return strings.ToLower(strings.Map(func(c rune) rune {
+		if unicode.IsLetter(c) || unicode.IsDigit(c) {
+			return c
+		}
+		return '_'
+	}, id))
+}
+
+type schemasByID []*schema.Schema
+
+func (s schemasByID) Len() int           { return len(s) }
+func (s schemasByID) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+func (s schemasByID) Less(i, j int) bool { return s[i].Id < s[j].Id }
@@ -0,0 +1,554 @@
+package commands
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// checkImports reports the packages which go source p uses but does not
+// import.
+func checkImports(t *testing.T, name string, p []byte) {
+	f, err := parser.ParseFile(token.NewFileSet(), name, p, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	imported := map[string]bool{}
+	for _, spec := range f.Imports {
+		path, _ := strconv.Unquote(spec.Path.Value)
+		imported[path[strings.LastIndex(path, "/")+1:]] = true
+	}
+	ast.Inspect(f, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Obj == nil && !imported[x.Name] {
+				t.Errorf("%v: %v.%v is used but %v is not imported", name, x.Name, sel.Sel.Name, x.Name)
+			}
+		}
+		return true
+	})
+}
+
+func TestGenerateGo(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	article := `id: article
+definitions:
+  status:
+    type: string
+    enum: [draft, published]
+properties:
+  id:
+    type: integer
+  status:
+    $ref: "#/definitions/status"
+  author:
+    $ref: "user.json#"
+required: [id]
+links:
+- title: Create
+  href: /articles
+  method: POST
+  schema:
+    properties:
+      title:
+        type: string
+    required: [title]
+`
+	if err := ioutil.WriteFile(path.Join(src, "article.yml"), []byte(article), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), renderScaffold("user").Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := GenerateGo(src, "api", out, newMeta(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ioutil.ReadFile(path.Join(out, "article.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"package api",
+		"Author *User          `json:\"author,omitempty\"`",
+		"ID     int64          `json:\"id\"`",
+		"type ArticleStatus string",
+		"ArticleStatusDraft     ArticleStatus = \"draft\"",
+		"type ArticleCreateRequest struct {\n\tTitle string `json:\"title\"`\n}",
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+
+	p, err = ioutil.ReadFile(path.Join(out, "user.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"import \"time\"",
+		"type UserCreatedAt = time.Time",
+		"CreatedAt UserCreatedAt `json:\"createdAt\"`",
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
+
+func TestGenerateGoEnum(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	user := `id: user
+properties:
+  status:
+    enum: [active, "it's", "it-s", null]
+  level:
+    type: integer
+    enum: [1, 2]
+`
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// no file is written if a file can not be generated.
+	if err := GenerateGo(src, "not-a-package", out, newMeta(), false); err == nil {
+		t.Error("invalid package name must be an error")
+	}
+	if infos, _ := ioutil.ReadDir(out); len(infos) != 0 {
+		t.Errorf("%v files are written", len(infos))
+	}
+
+	if err := GenerateGo(src, "api", out, newMeta(), false); err != nil {
+		t.Fatal(err)
+	}
+	p, err := ioutil.ReadFile(path.Join(out, "user.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"UserStatusActive UserStatus = \"active\"",
+		"UserStatusItS    UserStatus = \"it's\"",
+		"UserStatusItS2   UserStatus = \"it-s\"",
+		"UserLevelX1 UserLevel = 1",
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+	if strings.Contains(string(p), "nil") {
+		t.Errorf("null is written in\n%v", string(p))
+	}
+}
+
+func TestGenerateGoClient(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), renderScaffold("user").Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	metafile := path.Join(src, "meta.json")
+	if err := ioutil.WriteFile(metafile, []byte(`{"base_url": "https://api.example.com", "headers": ["X-Service-Token: AAA"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := ReadMeta(metafile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateGo(src, "api", out, meta, true); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ioutil.ReadFile(path.Join(out, "client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkImports(t, "client.go", p)
+	for _, s := range []string{
+		`const DefaultBaseURL = "https://api.example.com"`,
+		`addHeader(header, "X-Service-Token: AAA")`,
+		"func (c *Client) UserList(ctx context.Context) ([]User, error) {",
+		"func (c *Client) UserInfo(ctx context.Context, id string) (*User, error) {",
+		"func (c *Client) UserUpdate(ctx context.Context, id string, opts *UserUpdateRequest) error {",
+		`c.do(ctx, "PATCH", "/users/"+url.PathEscape(id), "application/json", body, nil)`,
+		"func (c *Client) UserDelete(ctx context.Context, id string) error {",
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
+
+func TestGenerateGoClientParams(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	user := `id: user
+definitions:
+  id:
+    type: string
+properties:
+  id:
+    $ref: "#/definitions/id"
+links:
+- title: Article
+  href: /users/{(%23%2Fdefinitions%2Fid)}/articles/{(%23%2Fdefinitions%2Fid)}
+  method: GET
+- title: Comment
+  href: /users/{c}/comments/{ctx}
+  method: GET
+`
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateGo(src, "api", out, newMeta(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ioutil.ReadFile(path.Join(out, "client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"func (c *Client) UserArticle(ctx context.Context, id string, id2 string) (*User, error) {",
+		`"/users/"+url.PathEscape(id)+"/articles/"+url.PathEscape(id2)`,
+		"func (c *Client) UserComment(ctx context.Context, cParam string, ctxParam string) (*User, error) {",
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
+
+// The generated client is run against a server which responds HEAD and an
+// empty 200 without a body.
+func TestGenerateGoClientEmptyBody(t *testing.T) {
+	gobin, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go is not installed")
+	}
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	user := `id: user
+properties:
+  name:
+    type: string
+links:
+- title: Ping
+  href: /users/{id}
+  method: HEAD
+- title: Info
+  href: /users/{id}
+  method: GET
+`
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateGo(src, "api", out, newMeta(), true); err != nil {
+		t.Fatal(err)
+	}
+	p, err := ioutil.ReadFile(path.Join(out, "client.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(p), "func (c *Client) UserPing(ctx context.Context, id string) error {") {
+		t.Errorf("HEAD must not have a result in\n%v", string(p))
+	}
+
+	test := `package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEmptyBody(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer s.Close()
+	c := NewClient()
+	c.BaseURL = s.URL
+	if err := c.UserPing(context.Background(), "1"); err != nil {
+		t.Errorf("UserPing: %v", err)
+	}
+	if _, err := c.UserInfo(context.Background(), "1"); err != nil {
+		t.Errorf("UserInfo: %v", err)
+	}
+}
+`
+	files := map[string]string{"go.mod": "module api\n\ngo 1.13\n", "empty_test.go": test}
+	for name, data := range files {
+		if err := ioutil.WriteFile(path.Join(out, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	cmd := exec.Command(gobin, "test", ".")
+	cmd.Dir = out
+	cmd.Env = append(os.Environ(), "GOFLAGS=", "GOPROXY=off")
+	if p, err := cmd.CombinedOutput(); err != nil {
+		t.Errorf("%v\n%s", err, p)
+	}
+}
+
+func TestGoName(t *testing.T) {
+	res := map[string]string{
+		"user":       "User",
+		"id":         "ID",
+		"userId":     "UserID",
+		"created_at": "CreatedAt",
+		"api-key":    "APIKey",
+		"1":          "X1",
+	}
+	for src, expected := range res {
+		if actual := goName(src); actual != expected {
+			t.Errorf("expected %v. but %v", expected, actual)
+		}
+	}
+}
+
+func TestGoParamName(t *testing.T) {
+	res := map[string]string{
+		"id":      "id",
+		"user_id": "userID",
+		"api_key": "apiKey",
+		"type":    "typeParam",
+		"c":       "cParam",
+	}
+	for src, expected := range res {
+		if actual := goParamName(src); actual != expected {
+			t.Errorf("expected %v. but %v", expected, actual)
+		}
+	}
+}
+
+func TestGenerateServer(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), renderScaffold("user").Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := GenerateServer(src, "api", out, "gorilla/mux"); err == nil {
+		t.Errorf("unknown router must be an error")
+	}
+	if warnings, err := GenerateServer(src, "api", out, "net/http"); err != nil {
+		t.Fatal(err)
+	} else if len(warnings) > 0 {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	if _, err := os.Stat(path.Join(out, "user.go")); err != nil {
+		t.Error(err)
+	}
+	p, err := ioutil.ReadFile(path.Join(out, "server.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkImports(t, "server.go", p)
+	for _, s := range []string{
+		"type Handler interface {",
+		"UserUpdate(ctx context.Context, id string, opts *UserUpdateRequest) error",
+		"UserDelete(ctx context.Context, id string) error",
+		`regexp.MustCompile("^/user/([^/]+)$")`,
+		"res, err := h.UserCreate(r.Context(), &opts)",
+		"encode(w, http.StatusCreated, res)",
+		"w.WriteHeader(http.StatusNoContent)",
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
+
+func TestGenerateServerParams(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	user := `id: user
+definitions:
+  id:
+    type: string
+  node:
+    properties:
+      children:
+        type: array
+        items:
+          $ref: "#/definitions/node"
+properties:
+  id:
+    $ref: "#/definitions/id"
+links:
+- title: Article
+  href: /users/{(%23%2Fdefinitions%2Fid)}/articles/{(%23%2Fdefinitions%2Fid)}
+  method: GET
+- title: Tree
+  href: /users/tree
+  method: POST
+  schema:
+    properties:
+      root:
+        $ref: "#/definitions/node"
+`
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	warnings, err := GenerateServer(src, "api", out, "net/http")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(warnings) != 1 || !strings.HasPrefix(warnings[0], "POST /users/tree: the request is not validated") {
+		t.Errorf("unexpected warnings %v", warnings)
+	}
+
+	p, err := ioutil.ReadFile(path.Join(out, "server.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkImports(t, "server.go", p)
+	s := "UserArticle(ctx context.Context, id string, id2 string) (*User, error)"
+	if !strings.Contains(string(p), s) {
+		t.Errorf("%q is not contained in\n%v", s, string(p))
+	}
+}
+
+func TestGenerateGoCollisions(t *testing.T) {
+	tests := []struct {
+		name     string
+		files    map[string]string
+		server   bool
+		expected string
+	}{
+		{
+			"resource named client",
+			map[string]string{"client.yml": "id: client\nproperties:\n  name:\n    type: string\n"},
+			false,
+			"client: client.go is written twice",
+		},
+		{
+			"resources written to the same file",
+			map[string]string{
+				"a.yml": "id: collision-a\nproperties:\n  name:\n    type: string\n",
+				"b.yml": "id: collision_a\nproperties:\n  id:\n    type: string\n",
+			},
+			false,
+			"collision_a: collision_a.go is written twice",
+		},
+		{
+			"links with the same title",
+			map[string]string{"user.yml": "id: user\nlinks:\n- title: Info\n  href: /users/{id}\n  method: GET\n- title: Info\n  href: /users/{id}/profile\n  method: GET\n"},
+			false,
+			"client.go: Client.UserInfo is declared twice",
+		},
+		{
+			"handlers with the same name",
+			map[string]string{"user.yml": "id: user\nlinks:\n- rel: self\n  href: /users/{id}\n  method: GET\n- rel: self\n  href: /users/{id}/profile\n  method: GET\n"},
+			true,
+			"server.go: Handler.UserSelf is declared twice",
+		},
+		{
+			"inline type named like a definition",
+			map[string]string{"user.yml": "id: user\ndefinitions:\n  name:\n    type: string\nproperties:\n  name:\n    properties:\n      first:\n        type: string\n"},
+			false,
+			"user.go: UserName is declared twice",
+		},
+	}
+	for _, test := range tests {
+		src, err := ioutil.TempDir("", "src")
+		if err != nil {
+			t.Fatal(err)
+		}
+		out, err := ioutil.TempDir("", "out")
+		if err != nil {
+			t.Fatal(err)
+		}
+		for name, data := range test.files {
+			if err := ioutil.WriteFile(path.Join(src, name), []byte(data), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if test.server {
+			_, err = GenerateServer(src, "api", out, "net/http")
+		} else {
+			err = GenerateGo(src, "api", out, newMeta(), true)
+		}
+		if err == nil || err.Error() != test.expected {
+			t.Errorf("%v: expected %v, but %v", test.name, test.expected, err)
+		}
+		if infos, _ := ioutil.ReadDir(out); len(infos) > 0 {
+			t.Errorf("%v: files are written", test.name)
+		}
+		os.RemoveAll(src)
+		os.RemoveAll(out)
+	}
+}
@@ -0,0 +1,419 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// GenerateServer writes go types of the resources under src and the server
+// of their links to out. router is the router the server is built on, and
+// only "net/http" is supported. The warnings are the links whose request
+// can not be validated.
+func GenerateServer(src, pkg, out, router string) ([]string, error) {
+	if err := isDir(src); err != nil {
+		return nil, err
+	}
+	if pkg == "" {
+		return nil, fmt.Errorf("pkg must be specified")
+	}
+	if router != "net/http" {
+		return nil, fmt.Errorf("unknown router: %v", router)
+	}
+	if err := createIfNotExist(out); err != nil {
+		return nil, err
+	}
+	g, err := newGoGenerator(src)
+	if err != nil {
+		return nil, err
+	}
+	files, err := g.typesFiles(pkg)
+	if err != nil {
+		return nil, err
+	}
+	p, warnings, err := g.serverFile(pkg)
+	if err != nil {
+		return nil, fmt.Errorf("server: %v", err)
+	}
+	if err := addFile(files, "server.go", p); err != nil {
+		return nil, fmt.Errorf("server: %v", err)
+	}
+	if err := checkDecls(files); err != nil {
+		return nil, err
+	}
+	return warnings, writeFiles(out, files)
+}
+
+// goRoute is a route of the generated router.
+type goRoute struct {
+	goMethod
+	// Pattern is the regular expression which matches the path.
+	Pattern string
+	// Status is the status code of a successful response.
+	Status string
+	// Schema is the dereferenced schema of the request, or empty if the
+	// request is not validated.
+	Schema string
+	// Fields is the type of the properties of the request, used to read
+	// query and form values.
+	Fields []goRouteField
+}
+
+type goRouteField struct {
+	Name string
+	Type string
+	// Item is the type of the items of an array.
+	Item string
+}
+
+// Signature returns the signature of the handler method of m.
+func (m goMethod) Signature() string {
+	args := []string{"ctx context.Context"}
+	for _, p := range m.Params {
+		args = append(args, p+" string")
+	}
+	if m.RequestType != "" {
+		args = append(args, "opts *"+m.RequestType)
+	}
+	result := "error"
+	switch {
+	case m.ResultType != "" && m.ResultSlice:
+		result = "([]" + m.ResultType + ", error)"
+	case m.ResultType != "":
+		result = "(*" + m.ResultType + ", error)"
+	}
+	return fmt.Sprintf("%v(%v) %v", m.Name, strings.Join(args, ", "), result)
+}
+
+// serverFile returns the go source of the handler interface and router of
+// the resources, and the warnings of the routes.
+func (g *goGenerator) serverFile(pkg string) ([]byte, []string, error) {
+	routes := []goRoute{}
+	warnings := []string{}
+	for _, r := range g.resources {
+		for _, l := range r.Links {
+			route, warning, err := g.serverRoute(r, l)
+			if err != nil {
+				return nil, nil, fmt.Errorf("%v %v: %v", l.Method, l.Href, err)
+			}
+			if warning != "" {
+				warnings = append(warnings, fmt.Sprintf("%v %v: %v", l.Method, l.Href, warning))
+			}
+			routes = append(routes, route)
+		}
+	}
+
+	p := struct {
+		Package string
+		Routes  []goRoute
+	}{
+		Package: pkg,
+		Routes:  routes,
+	}
+
+	funcs := template.FuncMap{"quote": strconv.Quote}
+	tmpl := template.Must(template.New("server").Funcs(funcs).Parse(goServerTmpl))
+	w := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(w, p); err != nil {
+		return nil, nil, err
+	}
+	src, err := format.Source(w.Bytes())
+	return src, warnings, err
+}
+
+// serverRoute returns the route of link l of resource r. The warning is
+// not empty if the request can not be validated.
+func (g *goGenerator) serverRoute(r *schema.Schema, l *schema.LinkDescription) (goRoute, string, error) {
+	route := goRoute{goMethod: g.clientMethod(r, l)}
+
+	pattern := "^"
+	for _, part := range l.HrefParts() {
+		if part.Param == "" {
+			pattern += regexp.QuoteMeta(part.Literal)
+		} else {
+			pattern += "([^/]+)"
+		}
+	}
+	route.Pattern = pattern + "$"
+
+	route.Status = goStatusCode(l.Response().StatusCode)
+
+	if route.RequestType == "" {
+		return route, "", nil
+	}
+	p, err := json.Marshal(l.Schema.Dereference())
+	if err != nil {
+		return route, "", err
+	}
+	// a schema which still has a recursive $ref can not be compiled alone.
+	warning := ""
+	if err := schema.ValidSchema(p); err != nil {
+		warning = fmt.Sprintf("the request is not validated: %v", err)
+	} else {
+		route.Schema = string(p)
+	}
+	if s := l.Schema.Alias(); s != nil {
+		for _, key := range s.PropertyNames() {
+			f := goRouteField{Name: key}
+			if a := s.Properties[key].Alias(); a != nil {
+				f.Type = schemaType(a)
+				if f.Type == "array" && len(a.Items) > 0 {
+					if item := a.Items[0].Alias(); item != nil {
+						f.Item = schemaType(item)
+					}
+				}
+			}
+			route.Fields = append(route.Fields, f)
+		}
+	}
+	return route, warning, nil
+}
+
+// goStatusCodes are the names of the status codes in net/http.
+var goStatusCodes = map[int]string{
+	http.StatusOK:        "http.StatusOK",
+	http.StatusCreated:   "http.StatusCreated",
+	http.StatusAccepted:  "http.StatusAccepted",
+	http.StatusNoContent: "http.StatusNoContent",
+}
+
+// goStatusCode returns the go expression of status code.
+func goStatusCode(code int) string {
+	if name, ok := goStatusCodes[code]; ok {
+		return name
+	}
+	return strconv.Itoa(code)
+}
+
+const goServerTmpl = `// Code generated by gendoc. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+
+	"github.com/xeipuuv/gojsonschema"
+)
+
+// Handler is implemented by the server of the API.
+type Handler interface {
+{{- range .Routes }}
+	// {{ .Name }} handles {{ .Method }} {{ .Href }}.
+	{{ .Signature }}
+{{- end }}
+}
+
+// HTTPError is returned by the methods of Handler to respond with the
+// status code. Other errors are responded with 500.
+type HTTPError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *HTTPError) Error() string {
+	return e.Message
+}
+
+type route struct {
+	method  string
+	pattern *regexp.Regexp
+	serve   func(w http.ResponseWriter, r *http.Request, params []string)
+}
+
+// Router routes the requests of the links to a Handler.
+type Router struct {
+	routes []route
+}
+
+// NewRouter returns the router which calls h.
+func NewRouter(h Handler) *Router {
+	rt := &Router{}
+{{- range $i, $r := .Routes }}
+{{- if .RequestType }}
+	fields{{ $i }} := map[string]field{
+{{- range .Fields }}
+		{{ quote .Name }}: {typ: {{ quote .Type }}, item: {{ quote .Item }}},
+{{- end }}
+	}
+	schema{{ $i }} := {{ if .Schema }}mustSchema({{ quote .Schema }}){{ else }}(*gojsonschema.Schema)(nil){{ end }}
+{{- end }}
+	rt.routes = append(rt.routes, route{
+		method:  {{ quote .Method }},
+		pattern: regexp.MustCompile({{ quote .Pattern }}),
+		serve: func(w http.ResponseWriter, r *http.Request, params []string) {
+{{- if .RequestType }}
+			var opts {{ .RequestType }}
+			if err := decode(r, {{ quote .EncType }}, fields{{ $i }}, schema{{ $i }}, &opts); err != nil {
+				writeError(w, err)
+				return
+			}
+{{- end }}
+			{{ if .ResultType }}res, err{{ else }}err{{ end }} := h.{{ .Name }}(r.Context(){{ range $n, $p := .Params }}, params[{{ $n }}]{{ end }}{{ if .RequestType }}, &opts{{ end }})
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+{{- if .ResultType }}
+			encode(w, {{ .Status }}, res)
+{{- else }}
+			w.WriteHeader({{ .Status }})
+{{- end }}
+		},
+	})
+{{- end }}
+	return rt
+}
+
+// ServeHTTP calls the handler method of the link which matches r.
+func (rt *Router) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	path := r.URL.EscapedPath()
+	allowed := false
+	for _, route := range rt.routes {
+		m := route.pattern.FindStringSubmatch(path)
+		if m == nil {
+			continue
+		}
+		if route.method != r.Method {
+			allowed = true
+			continue
+		}
+		params := make([]string, len(m)-1)
+		for i, p := range m[1:] {
+			v, err := url.PathUnescape(p)
+			if err != nil {
+				writeError(w, &HTTPError{StatusCode: http.StatusBadRequest, Message: err.Error()})
+				return
+			}
+			params[i] = v
+		}
+		route.serve(w, r, params)
+		return
+	}
+	if allowed {
+		writeError(w, &HTTPError{StatusCode: http.StatusMethodNotAllowed, Message: "method not allowed"})
+		return
+	}
+	writeError(w, &HTTPError{StatusCode: http.StatusNotFound, Message: "not found"})
+}
+
+// field is the type of a property, used to read query and form values.
+type field struct {
+	typ  string
+	item string
+}
+
+func mustSchema(s string) *gojsonschema.Schema {
+	schema, err := gojsonschema.NewSchema(gojsonschema.NewStringLoader(s))
+	if err != nil {
+		panic(err)
+	}
+	return schema
+}
+
+// decode reads the request into opts, and validates it against schema.
+// The query of GET, HEAD and DELETE, and the form of form encTypes are
+// converted to the types of fields.
+func decode(r *http.Request, encType string, fields map[string]field, schema *gojsonschema.Schema, opts interface{}) error {
+	var p []byte
+	switch {
+	case r.Method == "GET" || r.Method == "HEAD" || r.Method == "DELETE":
+		v, err := json.Marshal(formValues(r.URL.Query(), fields))
+		if err != nil {
+			return err
+		}
+		p = v
+	case encType == "application/x-www-form-urlencoded" || encType == "multipart/form-data":
+		if err := r.ParseMultipartForm(32 << 20); err != nil && err != http.ErrNotMultipart {
+			return &HTTPError{StatusCode: http.StatusBadRequest, Message: err.Error()}
+		}
+		v, err := json.Marshal(formValues(r.PostForm, fields))
+		if err != nil {
+			return err
+		}
+		p = v
+	default:
+		v, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return err
+		}
+		if len(v) == 0 {
+			v = []byte("{}")
+		}
+		p = v
+	}
+
+	if schema != nil {
+		result, err := schema.Validate(gojsonschema.NewBytesLoader(p))
+		if err != nil {
+			return &HTTPError{StatusCode: http.StatusBadRequest, Message: err.Error()}
+		}
+		if !result.Valid() {
+			return &HTTPError{StatusCode: http.StatusBadRequest, Message: result.Errors()[0].String()}
+		}
+	}
+	if err := json.Unmarshal(p, opts); err != nil {
+		return &HTTPError{StatusCode: http.StatusBadRequest, Message: err.Error()}
+	}
+	return nil
+}
+
+func formValues(values url.Values, fields map[string]field) map[string]interface{} {
+	m := map[string]interface{}{}
+	for k, vs := range values {
+		f := fields[k]
+		if f.typ == "array" {
+			items := []interface{}{}
+			for _, v := range vs {
+				items = append(items, formValue(v, f.item))
+			}
+			m[k] = items
+			continue
+		}
+		m[k] = formValue(vs[0], f.typ)
+	}
+	return m
+}
+
+// formValue converts v to typ, or returns v if it can not be converted.
+func formValue(v, typ string) interface{} {
+	switch typ {
+	case "integer", "number":
+		if n, err := strconv.ParseFloat(v, 64); err == nil {
+			return n
+		}
+	case "boolean":
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return v
+}
+
+func encode(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusInternalServerError
+	if e, ok := err.(*HTTPError); ok {
+		status = e.StatusCode
+	}
+	encode(w, status, map[string]string{"message": err.Error()})
+}
+`
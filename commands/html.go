@@ -2,21 +2,53 @@ package commands
 
 import (
 	"bytes"
+	"encoding/json"
+	"fmt"
 	"html/template"
-	"io/ioutil"
 	"os"
 	"path/filepath"
+	"regexp"
+	"sort"
 
 	"github.com/hiroosak/gendoc/schema"
+	"github.com/hiroosak/gendoc/snippet"
 )
 
 type htmlParam struct {
 	SchemaSlice schema.SchemaSlice
-	Meta        Meta
-	Overview    template.HTML
+	// Groups are the resources grouped by their group, listed in the
+	// sidebar.
+	Groups []resourceGroup
+	// Content is the groups of the resources written in the page.
+	Content   []resourceGroup
+	Meta      Meta
+	Overview  template.HTML
+	Changelog template.HTML
+	// Pages are the sections of the markdown files of the pages directory.
+	Pages []page
+	// Index is true if the overview, the pages, the changelog and the
+	// authentication are written in the page.
+	Index bool
+	// SearchIndex is embedded in the document for the search box.
+	SearchIndex []searchEntry
+	// SearchIndexURL is the file of the search index loaded by the page
+	// instead of SearchIndex in split mode.
+	SearchIndexURL string
 }
 
-func GenerateHTML(src, metafile, overviewfile, templatePath string) error {
+// searchIndexFile is the file of the search index in split mode.
+const searchIndexFile = "search-index.json"
+
+// GenerateHTML writes the document of the resources under src. The
+// overview, the changelog and the markdown files of pagesDir are put before
+// the resources. The examples are rendered for environment env of meta, and
+// the other environments can be switched in the document.
+//
+// The document is written to stdout as one page if splitDir is empty.
+// Otherwise index.html of the overview, the pages, the changelog and the
+// authentication, a page per resource and the search index are written to
+// splitDir.
+func GenerateHTML(src string, meta Meta, overviewfile, changelogfile, pagesDir, templatePath, env, splitDir string) error {
 	if err := isDir(src); err != nil {
 		return err
 	}
@@ -24,33 +56,122 @@ func GenerateHTML(src, metafile, overviewfile, templatePath string) error {
 	if err != nil {
 		return err
 	}
-	meta, err := readMeta(metafile)
+	if meta, err = meta.WithEnv(env); err != nil {
+		return err
+	}
+	overview, err := readOverview(overviewfile)
+	if err != nil {
+		return err
+	}
+	changelog, err := readOverview(changelogfile)
+	if err != nil {
+		return err
+	}
+	pages, err := readPages(pagesDir)
 	if err != nil {
 		return err
 	}
-	overview := readOverview(overviewfile)
 
+	pageOf := func(id string) string { return "" }
+	if splitDir != "" {
+		pageOf = pageFile
+		if err := uniquePages(resources); err != nil {
+			return err
+		}
+	}
+
+	groups := groupResources(resources)
 	param := htmlParam{
 		SchemaSlice: resources,
+		Groups:      groups,
+		Content:     groups,
 		Meta:        meta,
 		Overview:    overview,
+		Changelog:   changelog,
+		Pages:       pages,
+		Index:       true,
+		SearchIndex: searchIndex(resources, pageOf),
 	}
 
+	funcs := generateFuncMap(meta)
+	funcs["page"] = pageOf
 	files := templatePath + "/*.tpl"
 	tmpl := template.Must(template.New("root").
-		Funcs(generateFuncMap(meta)).
+		Funcs(funcs).
 		ParseGlob(files))
 
-	w := bytes.NewBuffer([]byte{})
-	if err := tmpl.ExecuteTemplate(w, "root", param); err != nil {
+	render := func(param htmlParam) ([]byte, error) {
+		w := bytes.NewBuffer([]byte{})
+		if err := tmpl.ExecuteTemplate(w, "root", param); err != nil {
+			return nil, err
+		}
+		return w.Bytes(), nil
+	}
+
+	if splitDir == "" {
+		p, err := render(param)
+		if err != nil {
+			return err
+		}
+		os.Stdout.Write(p)
+		return nil
+	}
+
+	out := map[string][]byte{}
+	searchData, err := json.Marshal(param.SearchIndex)
+	if err != nil {
+		return err
+	}
+	out[searchIndexFile] = searchData
+	param.SearchIndex = nil
+	param.SearchIndexURL = searchIndexFile
+
+	index := param
+	index.Content = nil
+	if out[pageFile("")], err = render(index); err != nil {
+		return err
+	}
+	for _, g := range groups {
+		for _, r := range g.Resources {
+			p := param
+			p.Index = false
+			p.Content = []resourceGroup{{Name: g.Name, Resources: schema.SchemaSlice{r}}}
+			if out[pageFile(r.Id)], err = render(p); err != nil {
+				return err
+			}
+		}
+	}
+	if err := createIfNotExist(splitDir); err != nil {
 		return err
 	}
+	return writeFiles(splitDir, out)
+}
 
-	w.WriteTo(os.Stdout)
+// pageFile returns the file of the page of resource id in split mode, or
+// of the index if id is empty.
+func pageFile(id string) string {
+	if id == "" {
+		return "index.html"
+	}
+	return notIDChar.ReplaceAllString(id, "-") + ".html"
+}
 
+// uniquePages returns an error if two resources, or a resource and the
+// index, are written to the same page.
+func uniquePages(resources schema.SchemaSlice) error {
+	ids := map[string]string{pageFile(""): "the index"}
+	for _, r := range resources {
+		file := pageFile(r.Id)
+		if other, ok := ids[file]; ok {
+			return fmt.Errorf("%v and %v are both written to %v", r.Id, other, file)
+		}
+		ids[file] = r.Id
+	}
 	return nil
 }
 
+// readResources reads the resources under src in the lexical order of
+// their paths, so the order is the same between runs.
 func readResources(src string) (schema.SchemaSlice, error) {
 	var resources schema.SchemaSlice
 	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
@@ -69,14 +190,30 @@ func readResources(src string) (schema.SchemaSlice, error) {
 	return resources, err
 }
 
-func readOverview(src string) template.HTML {
-	var overview template.HTML
-	if src != "" {
-		if p, err := ioutil.ReadFile(src); err == nil {
-			overview = template.HTML(string(p))
-		}
+// resourceGroup is a section of the resources in the document.
+type resourceGroup struct {
+	Name      string
+	Resources schema.SchemaSlice
+}
+
+// groupResources groups the resources by their group. The resources
+// without a group come first, and the groups follow in the order of their
+// names.
+func groupResources(resources schema.SchemaSlice) []resourceGroup {
+	byGroup := resources.GroupByString(func(s schema.Schema) string {
+		return s.Group
+	})
+	names := []string{}
+	for name := range byGroup {
+		names = append(names, name)
 	}
-	return overview
+	sort.Strings(names)
+
+	groups := []resourceGroup{}
+	for _, name := range names {
+		groups = append(groups, resourceGroup{Name: name, Resources: byGroup[name]})
+	}
+	return groups
 }
 
 func generateFuncMap(meta Meta) template.FuncMap {
@@ -87,5 +224,58 @@ func generateFuncMap(meta Meta) template.FuncMap {
 	funcs["headers"] = func() []string {
 		return meta.Headers
 	}
+	funcs["snippets"] = func(l *schema.LinkDescription) []snippet.Snippet {
+		return snippet.Render(meta.snippetRequest(l))
+	}
+	funcs["curl"] = func(l *schema.LinkDescription) string {
+		return snippet.Curl{}.Generate(meta.snippetRequest(l))
+	}
+	funcs["auth"] = meta.linkAuth
+	funcs["environments"] = func() []Environment {
+		return meta.Environments
+	}
+	funcs["envSnippets"] = func(l *schema.LinkDescription) []envSnippet {
+		return envSnippets(meta, l)
+	}
+	funcs["linkID"] = linkID
+	funcs["markdown"] = markdown
 	return funcs
 }
+
+// envSnippet is a snippet rendered for each environment.
+type envSnippet struct {
+	Name     string
+	Lang     string
+	Examples []envExample
+}
+
+type envExample struct {
+	Env     string
+	Default bool
+	Code    string
+}
+
+func envSnippets(meta Meta, l *schema.LinkDescription) []envSnippet {
+	snippets := []envSnippet{}
+	for _, m := range meta.envMetas() {
+		for i, s := range snippet.Render(m.snippetRequest(l)) {
+			if i == len(snippets) {
+				snippets = append(snippets, envSnippet{Name: s.Name, Lang: s.Lang})
+			}
+			snippets[i].Examples = append(snippets[i].Examples, envExample{
+				Env:     m.Env,
+				Default: m.Env == meta.Env,
+				Code:    s.Code,
+			})
+		}
+	}
+	return snippets
+}
+
+var notIDChar = regexp.MustCompile(`[^A-Za-z0-9_-]+`)
+
+// linkID returns an id of the element of link l, which can be used as a
+// css selector.
+func linkID(l *schema.LinkDescription) string {
+	return "link-" + notIDChar.ReplaceAllString(l.Method+"-"+l.Href, "-")
+}
@@ -0,0 +1,99 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+func TestGroupResources(t *testing.T) {
+	resources := schema.SchemaSlice{
+		{Id: "user", Group: "Accounts"},
+		{Id: "health"},
+		{Id: "article", Group: "Blog"},
+		{Id: "team", Group: "Accounts"},
+	}
+	groups := groupResources(resources)
+	expected := []struct {
+		name string
+		ids  []string
+	}{
+		{"", []string{"health"}},
+		{"Accounts", []string{"user", "team"}},
+		{"Blog", []string{"article"}},
+	}
+	if len(groups) != len(expected) {
+		t.Fatalf("expected %v groups, but %v", len(expected), groups)
+	}
+	for i, e := range expected {
+		if groups[i].Name != e.name || len(groups[i].Resources) != len(e.ids) {
+			t.Errorf("expected %v, but %v", e, groups[i])
+			continue
+		}
+		for j, id := range e.ids {
+			if groups[i].Resources[j].Id != id {
+				t.Errorf("expected %v, but %v", id, groups[i].Resources[j].Id)
+			}
+		}
+	}
+}
+
+func TestGenerateHTMLSplit(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	dst, err := ioutil.TempDir("", "dst")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dst)
+
+	files := map[string]string{
+		"user.yml":   "id: split-user\ngroup: Accounts\nproperties:\n  name:\n    type: string\nlinks:\n- title: Info\n  href: /users/{id}\n  method: GET\n",
+		"health.yml": "id: split-health\nlinks:\n- title: Check\n  href: /health\n  method: GET\n",
+	}
+	for name, data := range files {
+		if err := ioutil.WriteFile(path.Join(src, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := GenerateHTML(src, Meta{Title: "Split"}, "", "", "", "../template", "", dst); err != nil {
+		t.Fatal(err)
+	}
+
+	read := func(name string) string {
+		p, err := ioutil.ReadFile(path.Join(dst, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(p)
+	}
+	index := read("index.html")
+	user := read("split-user.html")
+	health := read("split-health.html")
+	search := read("search-index.json")
+
+	if strings.Contains(index, `name="split-user"`) || strings.Contains(health, `name="split-user"`) {
+		t.Error("split-user must be only in its page")
+	}
+	if !strings.Contains(user, `name="split-user"`) || !strings.Contains(health, `name="split-health"`) {
+		t.Error("the resources must be in their pages")
+	}
+	for _, page := range []string{index, user, health} {
+		if !strings.Contains(page, `href="split-user.html#split-user"`) || !strings.Contains(page, `href="split-health.html#split-health"`) {
+			t.Errorf("the sidebar must link to the pages of the resources: %v", page)
+		}
+		if !strings.Contains(page, "search-index.json") || strings.Contains(page, "split-user.html#split-user-name") {
+			t.Error("the search index must not be embedded")
+		}
+	}
+	if !strings.Contains(search, `"href":"split-user.html#split-user-name"`) {
+		t.Errorf("unexpected search index %v", search)
+	}
+}
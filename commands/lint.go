@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// lintRule returns the problems of resource r at their positions.
+type lintRule func(r *schema.Schema) []*schema.Error
+
+// lintRules are the lint rules of the project config by name.
+var lintRules = map[string]lintRule{
+	"resource-title": func(r *schema.Schema) []*schema.Error {
+		if strings.TrimSpace(r.Title) == "" {
+			return []*schema.Error{{Pos: r.Position(), Message: "resource has no title"}}
+		}
+		return nil
+	},
+	"link-title": func(r *schema.Schema) []*schema.Error {
+		problems := []*schema.Error{}
+		for _, l := range r.Links {
+			if strings.TrimSpace(l.Title) == "" {
+				problems = append(problems, &schema.Error{
+					Pos:     l.Position(),
+					Message: fmt.Sprintf("link %v %v has no title", l.Method, l.Href),
+				})
+			}
+		}
+		return problems
+	},
+	"link-description": func(r *schema.Schema) []*schema.Error {
+		problems := []*schema.Error{}
+		for _, l := range r.Links {
+			if strings.TrimSpace(l.Description) == "" {
+				problems = append(problems, &schema.Error{
+					Pos:     l.Position(),
+					Message: fmt.Sprintf("link %v %v has no description", l.Method, l.Href),
+				})
+			}
+		}
+		return problems
+	},
+	"property-description": func(r *schema.Schema) []*schema.Error {
+		problems := []*schema.Error{}
+		for _, key := range r.PropertyNames() {
+			p := r.Properties[key]
+			if a := p.Alias(); a == nil || strings.TrimSpace(a.Description) == "" {
+				problems = append(problems, &schema.Error{
+					Pos:     p.Position(),
+					Message: fmt.Sprintf("property %v has no description", key),
+				})
+			}
+		}
+		return problems
+	},
+}
+
+// LintSchemaTree checks the resources under src with the lint rules, and
+// returns the problems found. A problem starts with its position in the
+// file, or the id of the resource if the position is not known.
+func LintSchemaTree(src string, rules []string) ([]string, error) {
+	if err := isDir(src); err != nil {
+		return nil, err
+	}
+	resources, err := readResources(src)
+	if err != nil {
+		return nil, err
+	}
+	return lintResources(resources, rules)
+}
+
+// lintResources checks resources with the lint rules.
+func lintResources(resources schema.SchemaSlice, rules []string) ([]string, error) {
+	problems := []string{}
+	for i := range resources {
+		r := &resources[i]
+		for _, name := range rules {
+			rule, ok := lintRules[name]
+			if !ok {
+				return nil, fmt.Errorf("unknown lint rule %v", name)
+			}
+			for _, p := range rule(r) {
+				if p.Pos.String() == "" {
+					p.Pos.File = r.Id
+				}
+				problems = append(problems, fmt.Sprintf("%v (%v)", p, name))
+			}
+		}
+	}
+	return problems, nil
+}
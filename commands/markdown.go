@@ -0,0 +1,111 @@
+package commands
+
+import (
+	"bufio"
+	"bytes"
+	"html/template"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/russross/blackfriday"
+)
+
+// renderMarkdown renders markdown p as html.
+func renderMarkdown(p []byte) template.HTML {
+	return template.HTML(blackfriday.MarkdownCommon(p))
+}
+
+// descriptionPolicy removes the elements and attributes which can run
+// scripts from the html of the descriptions.
+var descriptionPolicy = bluemonday.UGCPolicy()
+
+// markdown renders description s of a schema as sanitized html.
+func markdown(s string) template.HTML {
+	p := blackfriday.MarkdownCommon([]byte(s))
+	return template.HTML(descriptionPolicy.SanitizeBytes(p))
+}
+
+func isMarkdownFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".md" || ext == ".markdown"
+}
+
+// page is a section of the document written in a markdown file, such as
+// Getting started.
+type page struct {
+	ID    string
+	Title string
+	Body  template.HTML
+}
+
+// readPages reads the markdown files in dir as pages in the order of their
+// names. The title of a page is its first heading, or the file name.
+func readPages(dir string) ([]page, error) {
+	if dir == "" {
+		return nil, nil
+	}
+	if err := isDir(dir); err != nil {
+		return nil, err
+	}
+	infos, err := ioutil.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := []string{}
+	for _, info := range infos {
+		if !info.IsDir() && isMarkdownFile(info.Name()) {
+			names = append(names, info.Name())
+		}
+	}
+	sort.Strings(names)
+
+	pages := []page{}
+	for _, name := range names {
+		p, err := ioutil.ReadFile(filepath.Join(dir, name))
+		if err != nil {
+			return nil, err
+		}
+		base := strings.TrimSuffix(name, filepath.Ext(name))
+		title := markdownTitle(p)
+		if title == "" {
+			title = base
+		}
+		pages = append(pages, page{
+			ID:    "page-" + notIDChar.ReplaceAllString(base, "-"),
+			Title: title,
+			Body:  renderMarkdown(p),
+		})
+	}
+	return pages, nil
+}
+
+// markdownTitle returns the text of the first heading of p.
+func markdownTitle(p []byte) string {
+	s := bufio.NewScanner(bytes.NewReader(p))
+	for s.Scan() {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "#") {
+			return strings.TrimSpace(strings.Trim(line, "#"))
+		}
+	}
+	return ""
+}
+
+// readOverview reads the file which is put in the document. A markdown
+// file is rendered as html, and others are put as is.
+func readOverview(src string) (template.HTML, error) {
+	if src == "" {
+		return "", nil
+	}
+	p, err := ioutil.ReadFile(src)
+	if err != nil {
+		return "", err
+	}
+	if isMarkdownFile(src) {
+		return renderMarkdown(p), nil
+	}
+	return template.HTML(p), nil
+}
@@ -0,0 +1,92 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestReadOverview(t *testing.T) {
+	dir, err := ioutil.TempDir("", "overview")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	if _, err := readOverview(path.Join(dir, "missing.md")); err == nil {
+		t.Errorf("missing overview must be an error")
+	}
+
+	html := path.Join(dir, "overview.html")
+	if err := ioutil.WriteFile(html, []byte("<p>*as is*</p>"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if overview, err := readOverview(html); err != nil || overview != "<p>*as is*</p>" {
+		t.Errorf("unexpected overview %v %v", overview, err)
+	}
+
+	md := path.Join(dir, "overview.md")
+	if err := ioutil.WriteFile(md, []byte("# Overview\n\nUse `curl`.\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	overview, err := readOverview(md)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(overview), "<h1>Overview</h1>") || !strings.Contains(string(overview), "<code>curl</code>") {
+		t.Errorf("unexpected overview %v", overview)
+	}
+}
+
+func TestReadPages(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pages")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"01-getting-started.md": "# Getting started\n\nSign up.\n",
+		"02-errors.md":          "Errors are JSON.\n",
+		"notes.txt":             "not a page",
+	}
+	for name, body := range files {
+		if err := ioutil.WriteFile(path.Join(dir, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	pages, err := readPages(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 pages, but %v", pages)
+	}
+	if pages[0].ID != "page-01-getting-started" || pages[0].Title != "Getting started" {
+		t.Errorf("unexpected page %v", pages[0])
+	}
+	if pages[1].Title != "02-errors" || !strings.Contains(string(pages[1].Body), "<p>Errors are JSON.</p>") {
+		t.Errorf("unexpected page %v", pages[1])
+	}
+}
+
+func TestMarkdown(t *testing.T) {
+	html := string(markdown("Use `id` of [users](/users).\n\n<script>alert(1)</script>\n\n* one\n* two\n"))
+	for _, s := range []string{
+		"<code>id</code>",
+		`<a href="/users"`,
+		"<li>one</li>",
+	} {
+		if !strings.Contains(html, s) {
+			t.Errorf("%v is not in %v", s, html)
+		}
+	}
+	if strings.Contains(html, "<script>") {
+		t.Errorf("script must be removed, but %v", html)
+	}
+	if html := string(markdown("")); html != "" {
+		t.Errorf("empty description must be empty, but %v", html)
+	}
+}
@@ -2,21 +2,168 @@ package commands
 
 import (
 	"encoding/json"
+	"fmt"
 	"io/ioutil"
+	"path/filepath"
+	"sort"
+
+	"github.com/ghodss/yaml"
+	"github.com/hiroosak/gendoc/schema"
+	"github.com/hiroosak/gendoc/snippet"
 )
 
 type Meta struct {
 	Title   string   `json:"title"`
 	BaseURL string   `json:"base_url"`
 	Headers []string `json:"headers"`
+	// Auth is the auth schemes of the API by name.
+	Auth map[string]*schema.AuthScheme `json:"auth"`
+	// Security is the names of the auth schemes of the links which do not
+	// declare their own security.
+	Security []string `json:"security"`
+	// Environments are the servers of the API such as staging.
+	Environments []Environment `json:"environments"`
+	// Env is the name of the environment of BaseURL and Headers.
+	Env string `json:"-"`
+	// commonHeaders are Headers without the headers of Env.
+	commonHeaders []string
+}
+
+// Environment is a server of the API.
+type Environment struct {
+	Name    string   `json:"name"`
+	BaseURL string   `json:"base_url"`
+	Headers []string `json:"headers"`
+}
+
+// WithEnv returns the meta whose BaseURL is the base url of environment
+// name, and Headers have its headers. The first environment is used if
+// name is empty.
+func (m Meta) WithEnv(name string) (Meta, error) {
+	if len(m.Environments) == 0 {
+		if name != "" {
+			return m, fmt.Errorf("environment %v is not in meta", name)
+		}
+		return m, nil
+	}
+	if name == "" {
+		name = m.Environments[0].Name
+	}
+	common := m.Headers
+	if m.Env != "" {
+		common = m.commonHeaders
+	}
+	for _, env := range m.Environments {
+		if env.Name != name {
+			continue
+		}
+		m.Env = env.Name
+		m.BaseURL = env.BaseURL
+		m.Headers = append(append([]string{}, common...), env.Headers...)
+		m.commonHeaders = common
+		return m, nil
+	}
+	return m, fmt.Errorf("environment %v is not in meta", name)
+}
+
+// envMetas returns the meta of each environment, or m itself if it has no
+// environments.
+func (m Meta) envMetas() []Meta {
+	if len(m.Environments) == 0 {
+		return []Meta{m}
+	}
+	metas := []Meta{}
+	for _, env := range m.Environments {
+		em, _ := m.WithEnv(env.Name)
+		metas = append(metas, em)
+	}
+	return metas
+}
+
+// linkAuth is an auth scheme of a link.
+type linkAuth struct {
+	Scheme *schema.AuthScheme
+	Scopes []string
+}
+
+// AuthSchemes returns the auth schemes sorted by name.
+func (m Meta) AuthSchemes() []*schema.AuthScheme {
+	names := []string{}
+	for name := range m.Auth {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	schemes := []*schema.AuthScheme{}
+	for _, name := range names {
+		schemes = append(schemes, m.Auth[name])
+	}
+	return schemes
 }
 
-func readMeta(path string) (Meta, error) {
-	meta := Meta{
+// linkAuth returns the auth schemes which link l accepts.
+func (m Meta) linkAuth(l *schema.LinkDescription) []linkAuth {
+	names := m.Security
+	if l.HasSecurity() {
+		names = l.Security
+	}
+	auth := []linkAuth{}
+	for _, name := range names {
+		a, ok := m.Auth[name]
+		if !ok {
+			continue
+		}
+		la := linkAuth{Scheme: a}
+		// only oauth2 has scopes.
+		if a.Type == "oauth2" {
+			la.Scopes = l.Scopes
+		}
+		auth = append(auth, la)
+	}
+	return auth
+}
+
+// securityErrors returns the errors of the links of resources whose
+// security names an auth scheme which is not in m.
+func (m Meta) securityErrors(resources schema.SchemaSlice) schema.ErrorList {
+	errs := schema.ErrorList{}
+	for i := range resources {
+		for _, l := range resources[i].Links {
+			for _, name := range l.Security {
+				if _, ok := m.Auth[name]; !ok {
+					errs = append(errs, &schema.Error{
+						Pos:     l.Position(),
+						Message: fmt.Sprintf("link %v %v: security %v is not in auth of meta", l.Method, l.Href, name),
+					})
+				}
+			}
+		}
+	}
+	return errs
+}
+
+// snippetRequest returns the example request of link l. The credential of
+// the first auth scheme of l is added to it.
+func (m Meta) snippetRequest(l *schema.LinkDescription) *snippet.Request {
+	r := snippet.NewRequest(l, m.BaseURL, m.Headers)
+	if auth := m.linkAuth(l); len(auth) > 0 {
+		r.Authenticate(auth[0].Scheme)
+	}
+	return r
+}
+
+// newMeta returns the meta used if no meta is specified.
+func newMeta() Meta {
+	return Meta{
 		Title:   "API Document",
 		BaseURL: "http://localhost",
 		Headers: []string{},
 	}
+}
+
+// ReadMeta reads the meta file at path, which is JSON or YAML by its
+// extension. The default meta is returned if path is empty.
+func ReadMeta(path string) (Meta, error) {
+	meta := newMeta()
 	if path == "" {
 		return meta, nil
 	}
@@ -26,9 +173,37 @@ func readMeta(path string) (Meta, error) {
 		return meta, err
 	}
 
-	if err := json.Unmarshal(rs, &meta); err != nil {
-		return meta, err
+	if isYAMLFile(path) {
+		err = yaml.Unmarshal(rs, &meta)
+	} else {
+		err = json.Unmarshal(rs, &meta)
+	}
+	if err != nil {
+		return meta, fmt.Errorf("%v: %v", path, err)
 	}
+	return meta, meta.init()
+}
+
+func isYAMLFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".yaml" || ext == ".yml"
+}
 
-	return meta, nil
+// init names the auth schemes and checks the security of m.
+func (m *Meta) init() error {
+	for name, a := range m.Auth {
+		if a == nil {
+			return fmt.Errorf("auth %v is empty", name)
+		}
+		a.Name = name
+		if err := a.Validate(); err != nil {
+			return fmt.Errorf("auth %v: %v", name, err)
+		}
+	}
+	for _, name := range m.Security {
+		if _, ok := m.Auth[name]; !ok {
+			return fmt.Errorf("security %v is not in auth", name)
+		}
+	}
+	return nil
 }
@@ -0,0 +1,153 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"testing"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+func TestMetaAuth(t *testing.T) {
+	dir, err := ioutil.TempDir("", "meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	metafile := path.Join(dir, "meta.json")
+	data := `{
+  "auth": {
+    "token": {"type": "bearer"},
+    "key": {"type": "apiKey", "in": "query", "name": "api_key"},
+    "oauth": {"type": "oauth2", "tokenUrl": "https://example.com/token"}
+  },
+  "security": ["token"]
+}`
+	if err := ioutil.WriteFile(metafile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := ReadMeta(metafile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if schemes := meta.AuthSchemes(); len(schemes) != 3 || schemes[0].Name != "key" || schemes[2].Name != "token" {
+		t.Errorf("unexpected schemes %v", schemes)
+	}
+
+	user := `{
+  "id": "meta-auth-user",
+  "links": [
+    {"href": "/users", "method": "GET"},
+    {"href": "/users", "method": "POST", "security": ["key", "oauth"], "scopes": ["write"]},
+    {"href": "/health", "method": "GET", "security": []}
+  ]
+}`
+	s, err := schema.NewSchemaFromBytes([]byte(user), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := meta.snippetRequest(s.Links[0])
+	if len(r.Header) != 1 || r.Header[0].Name != "Authorization" || r.Header[0].Value != "Bearer <TOKEN>" {
+		t.Errorf("unexpected header %v", r.Header)
+	}
+
+	auth := meta.linkAuth(s.Links[1])
+	// only oauth2 has the scopes.
+	if len(auth) != 2 || auth[0].Scheme.Name != "key" || len(auth[0].Scopes) != 0 ||
+		auth[1].Scheme.Name != "oauth" || len(auth[1].Scopes) != 1 || auth[1].Scopes[0] != "write" {
+		t.Errorf("unexpected auth %v", auth)
+	}
+	if r := meta.snippetRequest(s.Links[1]); r.FullURL() != "http://localhost/users?api_key=%3CAPI_KEY%3E" {
+		t.Errorf("unexpected url %v", r.FullURL())
+	}
+
+	if auth := meta.linkAuth(s.Links[2]); len(auth) != 0 {
+		t.Errorf("link without security must not be authenticated, but %v", auth)
+	}
+
+	if err := ioutil.WriteFile(metafile, []byte(`{"security": ["unknown"]}`), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadMeta(metafile); err == nil {
+		t.Errorf("unknown security must be an error")
+	}
+
+	for _, auth := range []string{
+		`{"type": "digest"}`,
+		`{"description": "no type"}`,
+		`{"type": "apiKey", "in": "cookie", "name": "key"}`,
+		`{"type": "apiKey", "in": "header"}`,
+	} {
+		if err := ioutil.WriteFile(metafile, []byte(`{"auth": {"a": `+auth+`}}`), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := ReadMeta(metafile); err == nil {
+			t.Errorf("auth %v must be an error", auth)
+		}
+	}
+}
+
+func TestMetaWithEnv(t *testing.T) {
+	meta := Meta{
+		BaseURL: "http://localhost",
+		Headers: []string{"Accept: application/json"},
+		Environments: []Environment{
+			{Name: "production", BaseURL: "https://api.example.com"},
+			{Name: "staging", BaseURL: "https://staging.example.com", Headers: []string{"X-Debug: 1"}},
+		},
+	}
+
+	m, err := meta.WithEnv("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Env != "production" || m.BaseURL != "https://api.example.com" || len(m.Headers) != 1 {
+		t.Errorf("unexpected default environment %v %v %v", m.Env, m.BaseURL, m.Headers)
+	}
+
+	m, err = m.WithEnv("staging")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.BaseURL != "https://staging.example.com" || len(m.Headers) != 2 || m.Headers[1] != "X-Debug: 1" {
+		t.Errorf("unexpected staging environment %v %v", m.BaseURL, m.Headers)
+	}
+	if metas := m.envMetas(); len(metas) != 2 || len(metas[0].Headers) != 1 || metas[1].Env != "staging" {
+		t.Errorf("unexpected environments %v", metas)
+	}
+
+	if _, err := meta.WithEnv("unknown"); err == nil {
+		t.Errorf("unknown environment must be an error")
+	}
+	if _, err := (Meta{}).WithEnv("staging"); err == nil {
+		t.Errorf("environment of meta without environments must be an error")
+	}
+}
+
+func TestReadMetaYAML(t *testing.T) {
+	dir, err := ioutil.TempDir("", "meta")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	metafile := path.Join(dir, "meta.yml")
+	data := `title: API Title
+base_url: https://api.example.com
+headers:
+- "X-Service-Token: AAA"
+`
+	if err := ioutil.WriteFile(metafile, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta, err := ReadMeta(metafile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if meta.Title != "API Title" || meta.BaseURL != "https://api.example.com" || len(meta.Headers) != 1 {
+		t.Errorf("unexpected meta %v", meta)
+	}
+}
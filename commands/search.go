@@ -0,0 +1,56 @@
+package commands
+
+import (
+	"strings"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// searchEntry is an entry of the search index embedded in the document.
+type searchEntry struct {
+	// Kind is "resource", "link" or "property".
+	Kind        string `json:"kind"`
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	// Href is the anchor of the entry in the document, after the page of
+	// the entry in split mode.
+	Href string `json:"href"`
+}
+
+// searchIndex returns the entries of the resources, their links and their
+// properties. pageOf returns the page of a resource, which is prepended to
+// the anchors.
+func searchIndex(resources schema.SchemaSlice, pageOf func(id string) string) []searchEntry {
+	index := []searchEntry{}
+	for i := range resources {
+		r := &resources[i]
+		page := pageOf(r.Id)
+		index = append(index, searchEntry{
+			Kind:        "resource",
+			Title:       r.Id,
+			Description: strings.TrimSpace(r.Description),
+			Href:        page + "#" + r.Id,
+		})
+		for _, l := range r.Links {
+			title := l.Method + " " + l.Href
+			if l.Title != "" {
+				title = title + " - " + l.Title
+			}
+			index = append(index, searchEntry{
+				Kind:        "link",
+				Title:       title,
+				Description: strings.TrimSpace(l.Description),
+				Href:        page + "#" + l.Method + "-" + l.Href,
+			})
+		}
+		for _, key := range r.PropertyNames() {
+			index = append(index, searchEntry{
+				Kind:        "property",
+				Title:       r.Id + "." + key,
+				Description: strings.TrimSpace(r.Properties[key].ResolveDescription()),
+				Href:        page + "#" + r.Id + "-" + key,
+			})
+		}
+	}
+	return index
+}
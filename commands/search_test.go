@@ -0,0 +1,40 @@
+package commands
+
+import (
+	"testing"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+func TestSearchIndex(t *testing.T) {
+	data := `{
+  "id": "search-user",
+  "description": "A user.",
+  "properties": {
+    "name": {"type": "string", "description": "name of the user"},
+    "id": {"type": "string"}
+  },
+  "links": [
+    {"title": "Info", "href": "/users/{id}", "method": "GET", "description": "Get a user."}
+  ]
+}`
+	s, err := schema.NewSchemaFromBytes([]byte(data), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	index := searchIndex(schema.SchemaSlice{*s}, func(string) string { return "" })
+	expected := []searchEntry{
+		{Kind: "resource", Title: "search-user", Description: "A user.", Href: "#search-user"},
+		{Kind: "link", Title: "GET /users/{id} - Info", Description: "Get a user.", Href: "#GET-/users/{id}"},
+		{Kind: "property", Title: "search-user.name", Description: "name of the user", Href: "#search-user-name"},
+		{Kind: "property", Title: "search-user.id", Href: "#search-user-id"},
+	}
+	if len(index) != len(expected) {
+		t.Fatalf("expected %v, but %v", expected, index)
+	}
+	for i := range expected {
+		if index[i] != expected[i] {
+			t.Errorf("expected %v, but %v", expected[i], index[i])
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package commands
+
+import (
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// tsMethod is a method of the generated fetch client.
+type tsMethod struct {
+	Name    string
+	Doc     []string
+	Method  string
+	Href    string
+	Path    string
+	Params  []string
+	EncType string
+	// RequestType is the type of the body, or empty if the link has no
+	// schema.
+	RequestType string
+	// ResultType is the type of the response, "void" if the response has
+	// no body.
+	ResultType string
+}
+
+// clientFile returns the typescript source of the client of the resources.
+func (g *tsGenerator) clientFile(meta Meta) ([]byte, error) {
+	methods := []tsMethod{}
+	imports := map[string]bool{}
+	for _, r := range g.resources {
+		for _, l := range r.Links {
+			m := g.clientMethod(r, l)
+			if m.RequestType != "" {
+				imports[m.RequestType] = true
+			}
+			if m.ResultType != "void" {
+				imports[strings.TrimSuffix(m.ResultType, "[]")] = true
+			}
+			methods = append(methods, m)
+		}
+	}
+	names := []string{}
+	for n := range imports {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+
+	type header struct{ Name, Value string }
+	headers := []header{}
+	for _, line := range meta.Headers {
+		if n := strings.Index(line, ":"); n > 0 {
+			headers = append(headers, header{strings.TrimSpace(line[0:n]), strings.TrimSpace(line[n+1:])})
+		}
+	}
+
+	p := struct {
+		Imports []string
+		Headers []header
+		Meta    Meta
+		Methods []tsMethod
+	}{
+		Imports: names,
+		Headers: headers,
+		Meta:    meta,
+		Methods: methods,
+	}
+
+	funcs := template.FuncMap{"quote": strconv.Quote, "join": strings.Join}
+	tmpl := template.Must(template.New("client").Funcs(funcs).Parse(tsClientTmpl))
+	w := bytes.NewBuffer([]byte{})
+	if err := tmpl.Execute(w, p); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+func (g *tsGenerator) clientMethod(r *schema.Schema, l *schema.LinkDescription) tsMethod {
+	m := tsMethod{
+		Name:    tsParamName(goName(r.Id) + goName(linkName(l))),
+		Method:  strings.ToUpper(l.Method),
+		Href:    l.Href,
+		EncType: l.EncType,
+	}
+	if m.Method == "" {
+		m.Method = "GET"
+	}
+	if m.EncType == "" {
+		m.EncType = "application/json"
+	}
+	if desc := strings.TrimSpace(l.Description); desc != "" {
+		m.Doc = strings.Split(desc, "\n")
+	}
+
+	path := []string{}
+	used := map[string]bool{}
+	for _, part := range l.HrefParts() {
+		if part.Param == "" {
+			path = append(path, strconv.Quote(part.Literal))
+			continue
+		}
+		param := uniqueName(tsParamName(part.Param), used)
+		m.Params = append(m.Params, param)
+		path = append(path, "encodeURIComponent("+param+")")
+	}
+	m.Path = strings.Join(path, " + ")
+	if m.Path == "" {
+		m.Path = `""`
+	}
+
+	if hasRequestSchema(r, l) {
+		m.RequestType = goRequestName(r, l)
+	}
+	switch {
+	case l.Response().Schema == nil:
+		m.ResultType = "void"
+	case hasTargetSchema(r, l):
+		m.ResultType = goResponseName(r, l)
+	case l.Rel == "instances":
+		m.ResultType = g.names[r] + "[]"
+	default:
+		m.ResultType = g.names[r]
+	}
+	return m
+}
+
+// tsKeywords are the reserved words which can not be parameter names.
+var tsKeywords = map[string]bool{
+	"break": true, "case": true, "catch": true, "class": true, "const": true,
+	"continue": true, "debugger": true, "default": true, "delete": true, "do": true,
+	"else": true, "enum": true, "export": true, "extends": true, "false": true,
+	"finally": true, "for": true, "function": true, "if": true, "import": true,
+	"in": true, "instanceof": true, "new": true, "null": true, "return": true,
+	"super": true, "switch": true, "this": true, "throw": true, "true": true,
+	"try": true, "typeof": true, "var": true, "void": true, "while": true,
+	"with": true, "yield": true, "let": true, "static": true, "await": true,
+	// names used in the generated methods.
+	"body": true, "encodeURIComponent": true,
+}
+
+// tsParamName returns a camel case identifier from s, such as "userID"
+// from "user_id".
+func tsParamName(s string) string {
+	name := goParamName(s)
+	if tsKeywords[name] {
+		name = name + "Param"
+	}
+	return name
+}
+
+const tsClientTmpl = `// Code generated by gendoc. DO NOT EDIT.
+{{ if .Imports }}
+import { {{ join .Imports ", " }} } from "./types";
+{{ end }}
+/** defaultBaseURL is the base url of the API. */
+export const defaultBaseURL = {{ quote .Meta.BaseURL }};
+
+/** defaultHeaders are sent with each request. */
+export const defaultHeaders: { [name: string]: string } = {
+{{- range .Headers }}
+  {{ quote .Name }}: {{ quote .Value }},
+{{- end }}
+};
+
+export interface ClientOptions {
+  /** baseURL is prepended to the path of each request. */
+  baseURL?: string;
+  /** headers are sent with each request instead of defaultHeaders. */
+  headers?: { [name: string]: string };
+  /** fetch sends the requests. The global fetch is used if omitted. */
+  fetch?: (input: string, init?: RequestInit) => Promise<Response>;
+}
+
+/** APIError is thrown when the API responds with an error status. */
+export class APIError extends Error {
+  constructor(public status: number, public body: string) {
+    super(status + " " + body.trim());
+  }
+}
+
+/** Client is the client of the API. */
+export class Client {
+  baseURL: string;
+  headers: { [name: string]: string };
+  private fetch: (input: string, init?: RequestInit) => Promise<Response>;
+
+  constructor(options: ClientOptions = {}) {
+    this.baseURL = options.baseURL || defaultBaseURL;
+    this.headers = options.headers || { ...defaultHeaders };
+    this.fetch = options.fetch || ((input, init) => fetch(input, init));
+  }
+{{ range .Methods }}
+  /**
+   * {{ .Name }} calls {{ .Method }} {{ .Href }}.
+{{- range .Doc }}
+   * {{ . }}
+{{- end }}
+   */
+  {{ .Name }}({{ range $i, $p := .Params }}{{ if $i }}, {{ end }}{{ $p }}: string{{ end }}{{ if .RequestType }}{{ if .Params }}, {{ end }}body?: {{ .RequestType }}{{ end }}): Promise<{{ .ResultType }}> {
+    return this.request<{{ .ResultType }}>({{ quote .Method }}, {{ .Path }}, {{ quote .EncType }}, {{ if .RequestType }}body{{ else }}undefined{{ end }});
+  }
+{{ end }}
+  private async request<T>(method: string, path: string, encType: string, body?: unknown): Promise<T> {
+    let url = this.baseURL + path;
+    const headers: { [name: string]: string } = { ...this.headers };
+    let payload: string | URLSearchParams | FormData | undefined;
+    if (body !== undefined) {
+      if (method === "GET" || method === "HEAD" || method === "DELETE") {
+        const q = toSearchParams(body).toString();
+        if (q !== "") {
+          url += "?" + q;
+        }
+      } else if (encType === "application/x-www-form-urlencoded") {
+        payload = toSearchParams(body);
+      } else if (encType === "multipart/form-data") {
+        const form = new FormData();
+        toSearchParams(body).forEach((v, k) => form.append(k, v));
+        payload = form;
+      } else {
+        payload = JSON.stringify(body);
+        headers["Content-Type"] = encType;
+      }
+    }
+
+    const res = await this.fetch(url, { method, headers, body: payload });
+    const text = await res.text();
+    if (!res.ok) {
+      throw new APIError(res.status, text);
+    }
+    return (text === "" ? undefined : JSON.parse(text)) as T;
+  }
+}
+
+/** toSearchParams encodes the fields of body as url values. */
+function toSearchParams(body: unknown): URLSearchParams {
+  const q = new URLSearchParams();
+  const fields = body as { [key: string]: unknown };
+  for (const k of Object.keys(fields)) {
+    const v = fields[k];
+    if (v === undefined) {
+      continue;
+    }
+    for (const item of Array.isArray(v) ? v : [v]) {
+      q.append(k, String(item));
+    }
+  }
+  return q;
+}
+`
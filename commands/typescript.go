@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// GenerateTS writes typescript declarations of the resources under src to
+// out/types.d.ts. If client is true, the fetch client of the links is
+// written to out/client.ts with the base url and headers of meta.
+func GenerateTS(src, out string, meta Meta, client bool) error {
+	if err := isDir(src); err != nil {
+		return err
+	}
+	if err := createIfNotExist(out); err != nil {
+		return err
+	}
+	g, err := newTSGenerator(src)
+	if err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(out, "types.d.ts"), g.typesFile(), filePerm); err != nil {
+		return err
+	}
+	if !client {
+		return nil
+	}
+
+	p, err := g.clientFile(meta)
+	if err != nil {
+		return fmt.Errorf("client: %v", err)
+	}
+	return ioutil.WriteFile(filepath.Join(out, "client.ts"), p, filePerm)
+}
+
+// tsGenerator maps the schemas to typescript types. The types are named
+// as the go types.
+type tsGenerator struct {
+	*goGenerator
+}
+
+func newTSGenerator(src string) (*tsGenerator, error) {
+	g, err := newGoGenerator(src)
+	if err != nil {
+		return nil, err
+	}
+	return &tsGenerator{g}, nil
+}
+
+// typesFile returns the declarations of all resources.
+func (g *tsGenerator) typesFile() []byte {
+	w := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(w, "// Code generated by gendoc. DO NOT EDIT.\n")
+	for _, r := range g.resources {
+		name := g.names[r]
+		g.declare(w, name, r, fmt.Sprintf("%v is the %v resource.", name, r.Id))
+
+		for _, key := range r.DefinitionNames() {
+			d := r.Definitions[key]
+			n := g.names[d]
+			g.declare(w, n, d, fmt.Sprintf("%v is the %v of %v.", n, key, r.Id))
+		}
+
+		for _, l := range r.Links {
+			if hasRequestSchema(r, l) {
+				n := goRequestName(r, l)
+				g.declare(w, n, l.Schema, fmt.Sprintf("%v is the request of %v %v.", n, l.Method, l.Href))
+			}
+			if hasTargetSchema(r, l) {
+				n := goResponseName(r, l)
+				g.declare(w, n, l.TargetSchema, fmt.Sprintf("%v is the response of %v %v.", n, l.Method, l.Href))
+			}
+		}
+	}
+	return w.Bytes()
+}
+
+// declare writes the declaration of s named name. An object is declared
+// as an interface, and the others as a type alias.
+func (g *tsGenerator) declare(w *bytes.Buffer, name string, s *schema.Schema, doc string) {
+	if a := s.Alias(); a != nil {
+		s = a
+	}
+	if desc := strings.TrimSpace(s.Description); desc != "" {
+		doc = doc + "\n" + desc
+	}
+	fmt.Fprintf(w, "\n")
+	writeTSComment(w, "", doc)
+
+	if n, ok := g.names[s]; ok && n != name {
+		fmt.Fprintf(w, "export type %v = %v;\n", name, n)
+		return
+	}
+	if len(s.Properties) == 0 || len(s.OneOf) > 0 || len(s.Enum) > 0 {
+		fmt.Fprintf(w, "export type %v = %v;\n", name, g.inlineType(s, "", []*schema.Schema{s}))
+		return
+	}
+	fmt.Fprintf(w, "export interface %v %v\n", name, g.objectType(s, "", []*schema.Schema{s}))
+}
+
+// tsType returns the typescript type of s. stack holds the schemas being
+// written inline to stop at recursive schemas.
+func (g *tsGenerator) tsType(s *schema.Schema, indent string, stack []*schema.Schema) string {
+	if n, ok := g.names[s]; ok {
+		return n
+	}
+	a := s.Alias()
+	if a == nil {
+		return "any"
+	}
+	if n, ok := g.names[a]; ok {
+		return n
+	}
+	for _, v := range stack {
+		if v == a {
+			return "any"
+		}
+	}
+	return g.inlineType(a, indent, append(stack, a))
+}
+
+// inlineType returns the typescript type of s which is not named.
+func (g *tsGenerator) inlineType(s *schema.Schema, indent string, stack []*schema.Schema) string {
+	typ := ""
+	switch {
+	case len(s.OneOf) > 0:
+		types := []string{}
+		for _, one := range s.OneOf {
+			types = append(types, g.tsType(one, indent, stack))
+		}
+		typ = strings.Join(types, " | ")
+	case len(s.Enum) > 0:
+		values := []string{}
+		for _, v := range s.Enum {
+			values = append(values, tsLiteral(v))
+		}
+		return strings.Join(values, " | ")
+	case len(s.Properties) > 0:
+		typ = g.objectType(s, indent, stack)
+	default:
+		switch schemaType(s) {
+		case "array":
+			typ = "any[]"
+			if len(s.Items) > 0 {
+				item := g.tsType(s.Items[0], indent, stack)
+				if strings.Contains(item, " ") {
+					item = "(" + item + ")"
+				}
+				typ = item + "[]"
+			}
+		case "object":
+			typ = "{ [key: string]: any }"
+		case "string":
+			typ = "string"
+		case "integer", "number":
+			typ = "number"
+		case "boolean":
+			typ = "boolean"
+		default:
+			typ = "any"
+		}
+	}
+	if isNullable(s) && typ != "any" {
+		typ = typ + " | null"
+	}
+	return typ
+}
+
+// objectType returns the type literal of the properties of s.
+func (g *tsGenerator) objectType(s *schema.Schema, indent string, stack []*schema.Schema) string {
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	w := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(w, "{\n")
+	for _, key := range s.PropertyNames() {
+		p := s.Properties[key]
+		if a := p.Alias(); a != nil {
+			writeTSComment(w, indent+"  ", strings.TrimSpace(a.Description))
+		}
+		optional := "?"
+		if required[key] {
+			optional = ""
+		}
+		fmt.Fprintf(w, "%v  %v%v: %v;\n", indent, tsPropertyName(key), optional, g.tsType(p, indent+"  ", stack))
+	}
+	fmt.Fprintf(w, "%v}", indent)
+	return w.String()
+}
+
+func writeTSComment(w *bytes.Buffer, indent, doc string) {
+	if doc == "" {
+		return
+	}
+	fmt.Fprintf(w, "%v/**\n", indent)
+	for _, line := range strings.Split(doc, "\n") {
+		fmt.Fprintf(w, "%v * %v\n", indent, strings.TrimSpace(line))
+	}
+	fmt.Fprintf(w, "%v */\n", indent)
+}
+
+// isNullable returns true if s has "null" type with other types.
+func isNullable(s *schema.Schema) bool {
+	if len(s.Type) < 2 {
+		return false
+	}
+	for _, t := range s.Type {
+		if t == "null" {
+			return true
+		}
+	}
+	return false
+}
+
+// tsLiteral returns the literal type of an enum value.
+func tsLiteral(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strconv.Quote(v)
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%v", v)
+}
+
+var tsIdentifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// tsPropertyName quotes key if it is not an identifier.
+func tsPropertyName(key string) string {
+	if tsIdentifier.MatchString(key) {
+		return key
+	}
+	return strconv.Quote(key)
+}
@@ -0,0 +1,139 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestGenerateTS(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	article := `id: article
+definitions:
+  status:
+    type: string
+    enum: [draft, published]
+  body:
+    oneOf:
+    - type: string
+    - $ref: "#/definitions/status"
+properties:
+  id:
+    type: integer
+  status:
+    $ref: "#/definitions/status"
+  author:
+    $ref: "user.json#"
+  note:
+    type: [string, "null"]
+required: [id]
+links:
+- title: Create
+  href: /articles
+  method: POST
+  schema:
+    properties:
+      title:
+        type: string
+    required: [title]
+`
+	if err := ioutil.WriteFile(path.Join(src, "article.yml"), []byte(article), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), renderScaffold("user").Bytes(), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := GenerateTS(src, out, newMeta(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ioutil.ReadFile(path.Join(out, "types.d.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"export interface Article {\n  id: number;\n  status?: ArticleStatus;\n  author?: User;\n  note?: string | null;\n}",
+		`export type ArticleStatus = "draft" | "published";`,
+		"export type ArticleBody = string | ArticleStatus;",
+		"export interface ArticleCreateRequest {\n  title: string;\n}",
+		"export interface User {",
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+
+	p, err = ioutil.ReadFile(path.Join(out, "client.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		`import { Article, ArticleCreateRequest, User, UserCreateRequest, UserUpdateRequest } from "./types";`,
+		"articleCreate(body?: ArticleCreateRequest): Promise<Article> {",
+		"userList(): Promise<User[]> {",
+		`userDelete(id: string): Promise<void> {`,
+		`return this.request<void>("DELETE", "/user/" + encodeURIComponent(id), "application/json", undefined);`,
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
+
+func TestGenerateTSClientParams(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+	out, err := ioutil.TempDir("", "out")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(out)
+
+	user := `id: user
+definitions:
+  id:
+    type: string
+properties:
+  id:
+    $ref: "#/definitions/id"
+links:
+- title: Article
+  href: /users/{(%23%2Fdefinitions%2Fid)}/articles/{(%23%2Fdefinitions%2Fid)}
+  method: GET
+`
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := GenerateTS(src, out, newMeta(), true); err != nil {
+		t.Fatal(err)
+	}
+
+	p, err := ioutil.ReadFile(path.Join(out, "client.ts"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range []string{
+		"userArticle(id: string, id2: string): Promise<User> {",
+		`"/users/" + encodeURIComponent(id) + "/articles/" + encodeURIComponent(id2)`,
+	} {
+		if !strings.Contains(string(p), s) {
+			t.Errorf("%q is not contained in\n%v", s, string(p))
+		}
+	}
+}
@@ -8,19 +8,81 @@ import (
 	"github.com/hiroosak/gendoc/schema"
 )
 
-func ValidSchemaTree(src string) error {
+// ValidSchemaTree validates the yaml and json files under src, the $refs
+// of the resources and the auth schemes of their links in meta. All the
+// errors are returned with their positions.
+func ValidSchemaTree(src string, meta Meta) error {
 	if err := isDir(src); err != nil {
 		return fmt.Errorf("src is not directory")
 	}
 
-	return filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
-		if info.IsDir() {
-			return nil
+	errs := schema.ErrorList{}
+	err := filepath.Walk(src, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
 		}
-		if js, err := schema.YamlFileToJson(path, info); err != nil {
+		if info.IsDir() || !isSchemaFile(path) {
 			return nil
-		} else {
-			return schema.ValidSchema(js)
 		}
+		errs.Add(schema.ValidateFile(path, info))
+		return nil
 	})
+	if err != nil {
+		return err
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	resources, err := readResources(src)
+	if err != nil {
+		return err
+	}
+	for i := range resources {
+		errs.Add(resources[i].ValidRefs())
+	}
+	errs.Add(meta.securityErrors(resources))
+	return errs.Err()
+}
+
+// ValidSchemaFiles checks files under src as ValidSchemaTree and
+// LintSchemaTree do. The resources are reloaded from src, so the $refs of
+// files to the other files are resolved against their current contents.
+// The lint problems are returned only if the files are valid.
+func ValidSchemaFiles(src string, files []string, meta Meta, rules []string) ([]string, error) {
+	checked := map[string]bool{}
+	errs := schema.ErrorList{}
+	for _, f := range files {
+		info, err := os.Stat(f)
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		checked[filepath.Clean(f)] = true
+		errs.Add(schema.ValidateFile(f, info))
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+
+	resources, err := readResources(src)
+	if err != nil {
+		return nil, err
+	}
+	targets := schema.SchemaSlice{}
+	for i := range resources {
+		if checked[filepath.Clean(resources[i].Position().File)] {
+			targets = append(targets, resources[i])
+		}
+	}
+	for i := range targets {
+		errs.Add(targets[i].ValidRefs())
+	}
+	errs.Add(meta.securityErrors(targets))
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	return lintResources(targets, rules)
 }
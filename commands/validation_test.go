@@ -0,0 +1,95 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+func TestValidSchemaTreeRefs(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	write := func(name, data string) {
+		if err := ioutil.WriteFile(path.Join(src, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("user.yml", "id: valid-user\ndefinitions:\n  id:\n    type: integer\nproperties:\n  id:\n    $ref: \"#/definitions/id\"\n")
+	write("article.yml", "id: valid-article\nproperties:\n  author:\n    $ref: valid-user.json#\n  author_id:\n    $ref: valid-user.json#/definitions/id\n")
+	if err := ValidSchemaTree(src, newMeta()); err != nil {
+		t.Errorf("refs to the other file must be valid, but %v", err)
+	}
+
+	write("article.yml", "id: valid-article\nproperties:\n  author:\n    $ref: valid-user.json#\n  author_id:\n    $ref: valid-user.json#/definitions/name\n")
+	expected := path.Join(src, "article.yml") + ":6:5: unresolved $ref valid-user.json#/definitions/name"
+	if err := ValidSchemaTree(src, newMeta()); err == nil || err.Error() != expected {
+		t.Errorf("expected %v, but %v", expected, err)
+	}
+}
+
+// A change of a file which breaks the files referring to it is reported
+// by checking them again.
+func TestValidSchemaFilesDependents(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	write := func(name, data string) {
+		if err := ioutil.WriteFile(path.Join(src, name), []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	user := path.Join(src, "user.yml")
+	article := path.Join(src, "article.yml")
+	write("user.yml", "id: watch-user\ntitle: User\ndefinitions:\n  id:\n    type: integer\n")
+	write("article.yml", "id: watch-article\nproperties:\n  author_id:\n    $ref: watch-user.json#/definitions/id\n")
+	write("tag.yml", "id: watch-tag\n")
+
+	problems, err := ValidSchemaFiles(src, []string{user, article}, newMeta(), []string{"resource-title"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{article + ":1:1: resource has no title (resource-title)"}
+	if !reflect.DeepEqual(problems, expected) {
+		t.Errorf("expected %v, but %v", expected, problems)
+	}
+
+	write("user.yml", "id: watch-user\ntitle: User\ndefinitions:\n  key:\n    type: integer\n")
+	g, err := buildDepGraph(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = ValidSchemaFiles(src, g.affected([]string{user}), newMeta(), nil)
+	if err == nil || err.Error() != article+":4:5: unresolved $ref watch-user.json#/definitions/id" {
+		t.Errorf("the broken ref of the dependent must be reported, but %v", err)
+	}
+}
+
+func TestValidSchemaTreeSecurity(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	user := "id: valid-security-user\nlinks:\n- href: /users\n  method: GET\n  security: [token]\n- href: /users\n  method: POST\n  security: [tokne]\n"
+	if err := ioutil.WriteFile(path.Join(src, "user.yml"), []byte(user), 0644); err != nil {
+		t.Fatal(err)
+	}
+	meta := newMeta()
+	meta.Auth = map[string]*schema.AuthScheme{"token": {Name: "token", Type: "bearer"}}
+	expected := path.Join(src, "user.yml") + ":6:3: link POST /users: security tokne is not in auth of meta"
+	if err := ValidSchemaTree(src, meta); err == nil || err.Error() != expected {
+		t.Errorf("expected %v, but %v", expected, err)
+	}
+}
@@ -0,0 +1,151 @@
+package commands
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// watchDelay is the time to wait for successive events before calling back.
+const watchDelay = 200 * time.Millisecond
+
+// Watch watches the schema files under src, and calls fn with the changed
+// files and the files which refer to them. It returns only on error.
+func Watch(src string, fn func(files []string)) error {
+	if err := isDir(src); err != nil {
+		return err
+	}
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer w.Close()
+
+	if err := watchTree(w, src); err != nil {
+		return err
+	}
+
+	changed := map[string]bool{}
+	var timer <-chan time.Time
+	for {
+		select {
+		case ev := <-w.Events:
+			if ev.Op&fsnotify.Create != 0 {
+				if info, err := os.Stat(ev.Name); err == nil && info.IsDir() {
+					watchTree(w, ev.Name)
+					continue
+				}
+			}
+			if !isSchemaFile(ev.Name) {
+				continue
+			}
+			changed[ev.Name] = true
+			timer = time.After(watchDelay)
+		case <-timer:
+			files := []string{}
+			for f := range changed {
+				files = append(files, f)
+			}
+			changed = map[string]bool{}
+			timer = nil
+
+			// the files which can not be parsed are reported by fn.
+			deps, err := buildDepGraph(src)
+			if err != nil {
+				fn(files)
+				continue
+			}
+			fn(deps.affected(files))
+		case err := <-w.Errors:
+			return err
+		}
+	}
+}
+
+// watchTree adds dir and its sub directories to w.
+func watchTree(w *fsnotify.Watcher, dir string) error {
+	return filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			return nil
+		}
+		return w.Add(path)
+	})
+}
+
+func isSchemaFile(path string) bool {
+	ext := filepath.Ext(path)
+	return ext == ".json" || ext == ".yaml" || ext == ".yml"
+}
+
+// depGraph is the reverse dependency graph of the schema files.
+type depGraph struct {
+	// dependents is the files which refer to the id.
+	dependents map[string][]string
+	// ids is the id defined in the file.
+	ids map[string]string
+}
+
+func buildDepGraph(src string) (*depGraph, error) {
+	resources, err := loadResources(src)
+	if err != nil {
+		return nil, err
+	}
+	g := &depGraph{
+		dependents: map[string][]string{},
+		ids:        map[string]string{},
+	}
+	for path, s := range resources {
+		path = filepath.Clean(path)
+		if s.Id != "" {
+			g.ids[path] = s.Id
+		}
+		for _, id := range s.ExternalRefs() {
+			g.dependents[id] = append(g.dependents[id], path)
+		}
+	}
+	return g, nil
+}
+
+// affected returns files and the files which refer to them directly or
+// indirectly.
+func (g *depGraph) affected(files []string) []string {
+	seen := map[string]bool{}
+	queue := []string{}
+	for _, f := range files {
+		f = filepath.Clean(f)
+		if !seen[f] {
+			seen[f] = true
+			queue = append(queue, f)
+		}
+	}
+	for len(queue) > 0 {
+		f := queue[0]
+		queue = queue[1:]
+
+		// a removed file is not in ids, so its id is guessed from the name.
+		id, ok := g.ids[f]
+		if !ok {
+			base := filepath.Base(f)
+			id = base[0 : len(base)-len(filepath.Ext(base))]
+		}
+		for _, d := range g.dependents[id] {
+			if !seen[d] {
+				seen[d] = true
+				queue = append(queue, d)
+			}
+		}
+	}
+
+	rs := []string{}
+	for f := range seen {
+		rs = append(rs, f)
+	}
+	sort.Strings(rs)
+	return rs
+}
@@ -0,0 +1,43 @@
+package commands
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"reflect"
+	"testing"
+)
+
+func TestDepGraphAffected(t *testing.T) {
+	src, err := ioutil.TempDir("", "src")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(src)
+
+	files := map[string]string{
+		"user.yml":    "id: user\ntype: object\n",
+		"article.yml": "id: article\nproperties:\n  author:\n    $ref: user.json#\n",
+		"comment.yml": "id: comment\nproperties:\n  article:\n    $ref: article.json#\n",
+		"tag.yml":     "id: tag\ntype: object\n",
+	}
+	for name, body := range files {
+		if err := ioutil.WriteFile(path.Join(src, name), []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	g, err := buildDepGraph(src)
+	if err != nil {
+		t.Fatal(err)
+	}
+	actual := g.affected([]string{path.Join(src, "user.yml")})
+	expected := []string{
+		path.Join(src, "article.yml"),
+		path.Join(src, "comment.yml"),
+		path.Join(src, "user.yml"),
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("expected %v. but %v", expected, actual)
+	}
+}
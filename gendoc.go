@@ -2,15 +2,28 @@ package main
 
 import (
 	"fmt"
+	"log"
 	"os"
 	"strings"
 
 	"github.com/hiroosak/gendoc/commands"
+	"github.com/hiroosak/gendoc/schema"
 
 	"gopkg.in/urfave/cli.v1"
 )
 
+// config is the project config read from the working directory.
+var config commands.Config
+
 func main() {
+	// the errors are printed by the actions.
+	if err := newApp().Run(os.Args); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newApp returns the app with the commands and their flags.
+func newApp() *cli.App {
 	srcFlag := cli.StringFlag{
 		Name:  "src",
 		Usage: "yaml files directory",
@@ -30,14 +43,99 @@ func main() {
 	}
 	overviewFlag := cli.StringFlag{
 		Name:  "overview",
-		Usage: "overview file",
+		Usage: "overview file (html or markdown)",
+	}
+	pagesFlag := cli.StringFlag{
+		Name:  "pages",
+		Usage: "directory of markdown pages put before the resources",
+	}
+	changelogFlag := cli.StringFlag{
+		Name:  "changelog",
+		Usage: "changelog file generated by the changelog command(html format)",
+	}
+	splitFlag := cli.StringFlag{
+		Name:  "split",
+		Usage: "directory to write a page per resource and the search index to, instead of stdout",
+	}
+	envFlag := cli.StringFlag{
+		Name:  "env",
+		Usage: "environment of meta rendered by default",
+	}
+	derefFlag := cli.BoolFlag{
+		Name:  "deref",
+		Usage: "inline resolved $ref",
+	}
+	toFlag := cli.StringFlag{
+		Name:  "to",
+		Usage: "output format (json or yaml)",
+		Value: "json",
+	}
+	cleanFlag := cli.BoolFlag{
+		Name:  "clean",
+		Usage: "remove generated files whose source no longer exists",
+	}
+	jobsFlag := cli.IntFlag{
+		Name:  "jobs",
+		Usage: "number of files converted in parallel (default: number of CPUs)",
+	}
+	watchFlag := cli.BoolFlag{
+		Name:  "watch",
+		Usage: "keep running and process changed files",
+	}
+	oldFlag := cli.StringFlag{
+		Name:  "old",
+		Usage: "yaml files directory of the old version",
+	}
+	newFlag := cli.StringFlag{
+		Name:  "new",
+		Usage: "yaml files directory of the new version",
+	}
+	fromFlag := cli.StringFlag{
+		Name:  "from",
+		Usage: "git revision of the old version",
+	}
+	toRevFlag := cli.StringFlag{
+		Name:  "to",
+		Usage: "git revision of the new version",
+		Value: "HEAD",
+	}
+	formatFlag := cli.StringFlag{
+		Name:  "format",
+		Usage: "output format (html or markdown)",
+		Value: "html",
+	}
+	exportFormatFlag := cli.StringFlag{
+		Name:  "format",
+		Usage: "collection format (postman)",
+		Value: "postman",
+	}
+	pkgFlag := cli.StringFlag{
+		Name:  "pkg",
+		Usage: "go package name",
+		Value: "api",
+	}
+	outFlag := cli.StringFlag{
+		Name:  "out",
+		Usage: "output directory",
+	}
+	clientFlag := cli.BoolFlag{
+		Name:  "client",
+		Usage: "generate the http client of the links",
+	}
+	routerFlag := cli.StringFlag{
+		Name:  "router",
+		Usage: "router of the generated server (net/http)",
+		Value: "net/http",
+	}
+	checkFlag := cli.BoolFlag{
+		Name:  "check",
+		Usage: "list files which are not formatted without rewriting",
 	}
 
 	app := cli.NewApp()
 	app.Name = "gendoc"
 	app.Usage = "make an document"
 	app.Version = "0.1"
-
 	app.Commands = []cli.Command{
 		cli.Command{
 			Name:   "init",
@@ -47,23 +145,74 @@ func main() {
 		cli.Command{
 			Name:   "doc",
 			Usage:  "Generate html from json schema",
+			Before: loadConfig,
 			Action: docAction,
-			Flags:  []cli.Flag{srcFlag, templateFlag, metaFlag, overviewFlag},
+			Flags:  []cli.Flag{srcFlag, templateFlag, metaFlag, overviewFlag, pagesFlag, changelogFlag, envFlag, splitFlag},
 		},
 		cli.Command{
 			Name:   "valid",
 			Usage:  "Validation YAML or JSON file",
+			Before: loadConfig,
 			Action: validAction,
-			Flags:  []cli.Flag{srcFlag},
+			Flags:  []cli.Flag{srcFlag, metaFlag, watchFlag},
 		},
 		cli.Command{
 			Name:   "gen",
 			Usage:  "Generate JSON from YAML",
+			Before: loadConfig,
 			Action: genAction,
-			Flags:  []cli.Flag{srcFlag, dstFlag},
+			Flags:  []cli.Flag{srcFlag, dstFlag, derefFlag, toFlag, cleanFlag, jobsFlag, watchFlag},
+		},
+		cli.Command{
+			Name:   "diff",
+			Usage:  "Report changes between two versions of json schema",
+			Action: diffAction,
+			Flags:  []cli.Flag{oldFlag, newFlag},
+		},
+		cli.Command{
+			Name:   "changelog",
+			Usage:  "Generate changelog between git revisions",
+			Before: loadConfig,
+			Action: changelogAction,
+			Flags:  []cli.Flag{srcFlag, fromFlag, toRevFlag, formatFlag},
+		},
+		cli.Command{
+			Name:   "export",
+			Usage:  "Export links as a collection of API client",
+			Before: loadConfig,
+			Action: exportAction,
+			Flags:  []cli.Flag{srcFlag, metaFlag, exportFormatFlag},
+		},
+		cli.Command{
+			Name:   "gen-go",
+			Usage:  "Generate go types from json schema",
+			Before: loadConfig,
+			Action: genGoAction,
+			Flags:  []cli.Flag{srcFlag, pkgFlag, outFlag, metaFlag, clientFlag},
+		},
+		cli.Command{
+			Name:   "gen-server",
+			Usage:  "Generate go server interface and router from json schema",
+			Before: loadConfig,
+			Action: genServerAction,
+			Flags:  []cli.Flag{srcFlag, pkgFlag, outFlag, routerFlag},
+		},
+		cli.Command{
+			Name:   "gen-ts",
+			Usage:  "Generate typescript types from json schema",
+			Before: loadConfig,
+			Action: genTSAction,
+			Flags:  []cli.Flag{srcFlag, outFlag, metaFlag, clientFlag},
+		},
+		cli.Command{
+			Name:   "fmt",
+			Usage:  "Rewrite YAML or JSON files in the canonical form",
+			Before: loadConfig,
+			Action: fmtAction,
+			Flags:  []cli.Flag{srcFlag, checkFlag},
 		},
 	}
-	app.Run(os.Args)
+	return app
 }
 
 func scaffoldAction(c *cli.Context) error {
@@ -72,36 +221,247 @@ func scaffoldAction(c *cli.Context) error {
 }
 
 func genAction(c *cli.Context) error {
-	src := c.String("src")
-	dst := c.String("dst")
-	if err := commands.GenerateJSON(src, dst); err != nil {
+	src := stringOption(c, "src", config.Src)
+	dst := stringOption(c, "dst", config.Dst)
+	to := stringOption(c, "to", config.Format)
+	opt := commands.GenerateOption{
+		Deref: c.Bool("deref"),
+		Clean: c.Bool("clean"),
+		Jobs:  c.Int("jobs"),
+	}
+
+	summary, err := generate(to, src, dst, opt)
+	for _, e := range summary.Failed {
+		fmt.Println(e)
+	}
+	if len(summary.Failed) > 0 && !c.Bool("watch") {
+		fmt.Println(summary)
+		return err
+	}
+	if err != nil && len(summary.Failed) == 0 {
 		fmt.Println(err)
 		fmt.Println("")
 		cli.ShowAppHelp(c)
 		return err
 	}
+	fmt.Println(summary)
+
+	if c.Bool("watch") {
+		return commands.Watch(src, func(files []string) {
+			opt.Files = files
+			summary, err := generate(to, src, dst, opt)
+			for _, e := range summary.Failed {
+				log.Println(e)
+			}
+			if err != nil && len(summary.Failed) == 0 {
+				log.Println(err)
+				return
+			}
+			log.Println(summary)
+		})
+	}
 	fmt.Println("ok.")
 	return nil
 }
 
-func validAction(c *cli.Context) error {
-	src := c.String("src")
-	if err := commands.ValidSchemaTree(src); err != nil {
+func generate(to, src, dst string, opt commands.GenerateOption) (commands.GenerateSummary, error) {
+	switch to {
+	case "json":
+		return commands.GenerateJSON(src, dst, opt)
+	case "yaml":
+		return commands.GenerateYAML(src, dst, opt)
+	}
+	return commands.GenerateSummary{}, fmt.Errorf("unknown format: %v", to)
+}
+
+func genGoAction(c *cli.Context) error {
+	src := stringOption(c, "src", config.Src)
+	pkg := c.String("pkg")
+	out := c.String("out")
+	client := c.Bool("client")
+
+	meta, err := readMeta(c)
+	if err == nil {
+		err = commands.GenerateGo(src, pkg, out, meta, client)
+	}
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		cli.ShowAppHelp(c)
+		return err
+	}
+	fmt.Println("ok.")
+	return nil
+}
+
+func genServerAction(c *cli.Context) error {
+	src := stringOption(c, "src", config.Src)
+	pkg := c.String("pkg")
+	out := c.String("out")
+	router := c.String("router")
+
+	warnings, err := commands.GenerateServer(src, pkg, out, router)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		cli.ShowAppHelp(c)
+		return err
+	}
+	for _, w := range warnings {
+		log.Printf("warning: %v", w)
+	}
+	fmt.Println("ok.")
+	return nil
+}
+
+func genTSAction(c *cli.Context) error {
+	src := stringOption(c, "src", config.Src)
+	out := c.String("out")
+	client := c.Bool("client")
+
+	meta, err := readMeta(c)
+	if err == nil {
+		err = commands.GenerateTS(src, out, meta, client)
+	}
+	if err != nil {
 		fmt.Println(err)
 		fmt.Println("")
+		cli.ShowAppHelp(c)
 		return err
 	}
 	fmt.Println("ok.")
 	return nil
 }
 
+func fmtAction(c *cli.Context) error {
+	src := stringOption(c, "src", config.Src)
+	check := c.Bool("check")
+	files, err := commands.FormatTree(src, check)
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		return err
+	}
+	for _, f := range files {
+		fmt.Println(f)
+	}
+	if check && len(files) > 0 {
+		return cli.NewExitError("", 1)
+	}
+	return nil
+}
+
+func diffAction(c *cli.Context) error {
+	changes, err := commands.DiffSchemaTree(c.String("old"), c.String("new"))
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		cli.ShowAppHelp(c)
+		return err
+	}
+	for _, change := range changes {
+		fmt.Println(change)
+	}
+	if schema.HasBreaking(changes) {
+		return cli.NewExitError("", 1)
+	}
+	return nil
+}
+
+func changelogAction(c *cli.Context) error {
+	src := stringOption(c, "src", config.Src)
+	from := c.String("from")
+	to := c.String("to")
+	format := c.String("format")
+
+	if err := commands.GenerateChangelog(from, to, src, format); err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		cli.ShowAppHelp(c)
+		return err
+	}
+	return nil
+}
+
+func validAction(c *cli.Context) error {
+	src := stringOption(c, "src", config.Src)
+	meta, err := readMeta(c)
+	if err != nil {
+		fmt.Println(err)
+		return err
+	}
+	if err := commands.ValidSchemaTree(src, meta); err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		if !c.Bool("watch") {
+			return err
+		}
+	} else if problems, err := commands.LintSchemaTree(src, config.Lint); err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		if !c.Bool("watch") {
+			return err
+		}
+	} else if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Println(p)
+		}
+		if !c.Bool("watch") {
+			return cli.NewExitError("", 1)
+		}
+	} else {
+		fmt.Println("ok.")
+	}
+
+	if c.Bool("watch") {
+		return commands.Watch(src, func(files []string) {
+			problems, err := commands.ValidSchemaFiles(src, files, meta, config.Lint)
+			switch {
+			case err != nil:
+				log.Print(err)
+			case len(problems) > 0:
+				for _, p := range problems {
+					log.Print(p)
+				}
+			default:
+				log.Printf("%v: ok.", strings.Join(files, ", "))
+			}
+		})
+	}
+	return nil
+}
+
+func exportAction(c *cli.Context) error {
+	src := stringOption(c, "src", config.Src)
+	format := c.String("format")
+
+	meta, err := readMeta(c)
+	if err == nil {
+		err = commands.ExportCollection(src, meta, format)
+	}
+	if err != nil {
+		fmt.Println(err)
+		fmt.Println("")
+		cli.ShowAppHelp(c)
+		return err
+	}
+	return nil
+}
+
 func docAction(c *cli.Context) error {
-	src := c.String("src")
-	meta := c.String("meta")
-	template := c.String("template")
-	overview := c.String("overview")
+	src := stringOption(c, "src", config.Src)
+	template := stringOption(c, "template", config.Template)
+	overview := stringOption(c, "overview", config.Overview)
+	pages := stringOption(c, "pages", config.Pages)
+	changelog := c.String("changelog")
+	env := c.String("env")
+	split := c.String("split")
 
-	if err := commands.GenerateHTML(src, meta, overview, template); err != nil {
+	meta, err := readMeta(c)
+	if err == nil {
+		err = commands.GenerateHTML(src, meta, overview, changelog, pages, template, env, split)
+	}
+	if err != nil {
 		fmt.Println(err)
 		fmt.Println("")
 		cli.ShowAppHelp(c)
@@ -110,6 +470,35 @@ func docAction(c *cli.Context) error {
 	return nil
 }
 
+// loadConfig reads the project config. It is read only by the commands
+// which use it, so that a broken config does not break the others.
+func loadConfig(c *cli.Context) error {
+	var err error
+	if config, err = commands.ReadConfig(commands.ConfigFile); err != nil {
+		fmt.Println(err)
+		return err
+	}
+	return nil
+}
+
+// stringOption returns the flag name if it is set, or value of the project
+// config if it is not empty, or the default of the flag.
+func stringOption(c *cli.Context, name, value string) string {
+	if c.IsSet(name) || value == "" {
+		return c.String(name)
+	}
+	return value
+}
+
+// readMeta returns the meta of the meta flag, or the meta of the project
+// config if the flag is not set.
+func readMeta(c *cli.Context) (commands.Meta, error) {
+	if !c.IsSet("meta") && config.Meta != nil {
+		return *config.Meta, nil
+	}
+	return commands.ReadMeta(c.String("meta"))
+}
+
 func getGoPath() string {
 	paths := strings.Split(os.Getenv("GOPATH"), ":")
 	return paths[len(paths)-1]
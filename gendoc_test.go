@@ -0,0 +1,54 @@
+package main
+
+import (
+	"io/ioutil"
+	"os"
+	"path"
+	"strings"
+	"testing"
+)
+
+func TestConfigOnlyForCommandsUsingIt(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	dir, err := ioutil.TempDir("", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+
+	src := path.Join(dir, "src")
+	if err := os.Mkdir(src, 0755); err != nil {
+		t.Fatal(err)
+	}
+	body := `{"id": "config-user", "type": "object"}`
+	if err := ioutil.WriteFile(path.Join(src, "config-user.json"), []byte(body), 0644); err != nil {
+		t.Fatal(err)
+	}
+	diff := []string{"gendoc", "diff", "-old", src, "-new", src}
+	valid := []string{"gendoc", "valid", "-src", src}
+
+	// without gendoc.yml
+	if err := newApp().Run(diff); err != nil {
+		t.Errorf("diff must not need gendoc.yml. %v", err)
+	}
+	if err := newApp().Run(valid); err != nil {
+		t.Errorf("valid must not need gendoc.yml. %v", err)
+	}
+
+	if err := ioutil.WriteFile("gendoc.yml", []byte("src: [\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := newApp().Run(diff); err != nil {
+		t.Errorf("diff must not read a broken gendoc.yml. %v", err)
+	}
+	if err := newApp().Run(valid); err == nil || !strings.Contains(err.Error(), "gendoc.yml") {
+		t.Errorf("valid must report a broken gendoc.yml. %v", err)
+	}
+}
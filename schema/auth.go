@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"encoding/base64"
+	"fmt"
+)
+
+// AuthScheme is an authentication scheme of an API, declared in meta.
+type AuthScheme struct {
+	// Name is the key of the scheme in meta.
+	Name string `json:"-"`
+	// Type is "bearer", "basic", "apiKey" or "oauth2".
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	// In is "header" or "query" where an apiKey is sent.
+	In string `json:"in"`
+	// Param is the name of the header or query of an apiKey.
+	Param            string `json:"name"`
+	AuthorizationURL string `json:"authorizationUrl"`
+	TokenURL         string `json:"tokenUrl"`
+	// Scopes are the descriptions of the scopes of oauth2.
+	Scopes map[string]string `json:"scopes"`
+	// Example is the credential used in request examples instead of the
+	// placeholder.
+	Example string `json:"example"`
+}
+
+// Validate returns an error if the type of a is not supported, or an apiKey
+// does not have where it is sent.
+func (a *AuthScheme) Validate() error {
+	switch a.Type {
+	case "bearer", "basic", "oauth2":
+		return nil
+	case "apiKey":
+		if a.Param == "" {
+			return fmt.Errorf("apiKey must have name")
+		}
+		if a.In != "header" && a.In != "query" {
+			return fmt.Errorf("in of apiKey must be header or query, but %q", a.In)
+		}
+		return nil
+	case "":
+		return fmt.Errorf("type must be specified")
+	}
+	return fmt.Errorf("unsupported type %q", a.Type)
+}
+
+// Credential returns the header or query which carries the credential.
+// query is true if it is sent in the query.
+func (a *AuthScheme) Credential() (name, value string, query bool) {
+	switch a.Type {
+	case "basic":
+		if a.Example != "" {
+			return "Authorization", "Basic " + a.Example, false
+		}
+		return "Authorization", "Basic " + base64.StdEncoding.EncodeToString([]byte("username:password")), false
+	case "apiKey":
+		value = a.Example
+		if value == "" {
+			value = "<API_KEY>"
+		}
+		return a.Param, value, a.In == "query"
+	case "oauth2":
+		value = a.Example
+		if value == "" {
+			value = "<ACCESS_TOKEN>"
+		}
+		return "Authorization", "Bearer " + value, false
+	case "bearer":
+		value = a.Example
+		if value == "" {
+			value = "<TOKEN>"
+		}
+		return "Authorization", "Bearer " + value, false
+	}
+	// a is not valid.
+	return "", "", false
+}
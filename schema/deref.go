@@ -0,0 +1,81 @@
+package schema
+
+// Dereference returns the source document of s with every resolvable $ref
+// replaced by the schema it points to. A $ref is left in place when
+// inlining it would recurse forever, or when it can not be resolved.
+func (s *Schema) Dereference() map[string]interface{} {
+	d, _ := s.deref(s.raw, []*Schema{s}).(map[string]interface{})
+	return d
+}
+
+// deref walks v and inlines references resolved against s. stack holds the
+// schemas being expanded, so a reference back into one of them is a cycle.
+func (s *Schema) deref(v interface{}, stack []*Schema) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			target := s.resolveReference(s.Id, ref)
+			if target == nil || target.raw == nil || inStack(stack, target) {
+				return copyMap(v)
+			}
+			return target.deref(target.raw, append(stack, target))
+		}
+		m := make(map[string]interface{}, len(v))
+		for key, value := range v {
+			switch {
+			case literalKeys[key]:
+				// the values are data, which may have "$ref" as a key.
+				m[key] = value
+			case namedSchemaKeys[key]:
+				m[key] = s.derefNamed(value, stack)
+			default:
+				m[key] = s.deref(value, stack)
+			}
+		}
+		return m
+	case []interface{}:
+		l := make([]interface{}, len(v))
+		for i, value := range v {
+			l[i] = s.deref(value, stack)
+		}
+		return l
+	}
+	return v
+}
+
+// literalKeys are the keywords whose values are not schemas.
+var literalKeys = map[string]bool{"example": true, "enum": true, "default": true}
+
+// namedSchemaKeys are the keywords whose values are schemas by name, so
+// that a schema can be named like a literal keyword.
+var namedSchemaKeys = map[string]bool{"properties": true, "definitions": true, "patternProperties": true}
+
+// derefNamed inlines the references of a map of schemas by name.
+func (s *Schema) derefNamed(v interface{}, stack []*Schema) interface{} {
+	named, ok := v.(map[string]interface{})
+	if !ok {
+		return s.deref(v, stack)
+	}
+	m := make(map[string]interface{}, len(named))
+	for key, value := range named {
+		m[key] = s.deref(value, stack)
+	}
+	return m
+}
+
+func inStack(stack []*Schema, s *Schema) bool {
+	for _, v := range stack {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func copyMap(m map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(m))
+	for k, v := range m {
+		c[k] = v
+	}
+	return c
+}
@@ -0,0 +1,102 @@
+package schema
+
+import "testing"
+
+func TestDereference(t *testing.T) {
+	var jsonstr = `{
+		"id": "node",
+		"definitions": {
+			"name": {
+				"type": "string",
+				"example": "root"
+			},
+			"node": {
+				"type": "object",
+				"properties": {
+					"name": {
+						"$ref": "#/definitions/name"
+					},
+					"child": {
+						"$ref": "#/definitions/node"
+					}
+				}
+			}
+		},
+		"properties": {
+			"name": {
+				"$ref": "#/definitions/name"
+			},
+			"parent": {
+				"$ref": "#/definitions/node"
+			},
+			"self": {
+				"$ref": "#"
+			}
+		}
+	}`
+
+	s, err := NewSchemaFromBytes([]byte(jsonstr), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := s.Dereference()
+	props := d["properties"].(map[string]interface{})
+
+	name := props["name"].(map[string]interface{})
+	if name["type"] != "string" {
+		t.Errorf("name is not inlined. %v", name)
+	}
+	self := props["self"].(map[string]interface{})
+	if self["$ref"] != "#" {
+		t.Errorf("self reference must be kept. %v", self)
+	}
+	parent := props["parent"].(map[string]interface{})
+	parentProps := parent["properties"].(map[string]interface{})
+	if parentProps["name"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("parent.name is not inlined. %v", parentProps)
+	}
+	child := parentProps["child"].(map[string]interface{})
+	if child["$ref"] != "#/definitions/node" {
+		t.Errorf("recursive reference must be kept. %v", child)
+	}
+}
+
+func TestDereferenceLiterals(t *testing.T) {
+	var jsonstr = `{
+		"id": "literal",
+		"definitions": {
+			"name": {
+				"type": "string"
+			}
+		},
+		"type": "object",
+		"example": {"$ref": "#/definitions/name"},
+		"enum": [{"$ref": "#/definitions/name"}],
+		"default": {"$ref": "#/definitions/name"},
+		"properties": {
+			"example": {
+				"$ref": "#/definitions/name"
+			}
+		}
+	}`
+
+	s, err := NewSchemaFromBytes([]byte(jsonstr), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	d := s.Dereference()
+	for _, key := range []string{"example", "default"} {
+		v := d[key].(map[string]interface{})
+		if v["$ref"] != "#/definitions/name" {
+			t.Errorf("%v must be kept as is. %v", key, v)
+		}
+	}
+	enum := d["enum"].([]interface{})[0].(map[string]interface{})
+	if enum["$ref"] != "#/definitions/name" {
+		t.Errorf("enum must be kept as is. %v", enum)
+	}
+	props := d["properties"].(map[string]interface{})
+	if props["example"].(map[string]interface{})["type"] != "string" {
+		t.Errorf("a property named example is not inlined. %v", props)
+	}
+}
@@ -0,0 +1,339 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is a difference between two versions of an API.
+type Change struct {
+	Breaking bool
+	Resource string
+	// Link is "METHOD href", or empty if the change is on the resource.
+	Link    string
+	Message string
+}
+
+func (c Change) String() string {
+	kind := "non-breaking"
+	if c.Breaking {
+		kind = "breaking"
+	}
+	target := c.Resource
+	if c.Link != "" {
+		target = target + " " + c.Link
+	}
+	return fmt.Sprintf("[%v] %v: %v", kind, target, c.Message)
+}
+
+// HasBreaking returns true if changes contain a breaking change.
+func HasBreaking(changes []Change) bool {
+	for _, c := range changes {
+		if c.Breaking {
+			return true
+		}
+	}
+	return false
+}
+
+// APIShape is the resolved shape of resources compared by DiffAPI.
+// References are resolved when it is created, so it must be created
+// before other schemas with the same ids are loaded.
+type APIShape map[string]resourceShape
+
+type resourceShape struct {
+	fields fieldsShape
+	links  map[string]linkShape
+}
+
+type linkShape struct {
+	// request is nil if the link has no schema.
+	request fieldsShape
+	// response is nil if the link has no targetSchema.
+	response fieldsShape
+}
+
+// fieldsShape is the properties of a schema flattened by their path,
+// such as "author.name" or "tags[]".
+type fieldsShape map[string]fieldShape
+
+type fieldShape struct {
+	types    []string
+	format   string
+	enum     []string
+	required bool
+}
+
+// NewAPIShape resolves the resources in ss.
+func NewAPIShape(ss SchemaSlice) APIShape {
+	shape := APIShape{}
+	for i := range ss {
+		s := &ss[i]
+		r := resourceShape{
+			fields: fieldsShape{},
+			links:  map[string]linkShape{},
+		}
+		r.fields.add(s, "", nil)
+		for _, l := range s.Links {
+			// a link without schema or targetSchema refers to the resource.
+			ls := linkShape{}
+			if l.Schema != nil && l.Schema.CurrentRef != s.CurrentRef {
+				ls.request = fieldsShape{}
+				ls.request.add(l.Schema, "", nil)
+			}
+			if l.TargetSchema != nil && l.TargetSchema.CurrentRef != s.CurrentRef {
+				ls.response = fieldsShape{}
+				ls.response.add(l.TargetSchema, "", nil)
+			}
+			r.links[l.Method+" "+l.Href] = ls
+		}
+		shape[s.Id] = r
+	}
+	return shape
+}
+
+// add flattens the properties of s into f. stack holds the schemas being
+// flattened to stop at recursive schemas.
+func (f fieldsShape) add(s *Schema, prefix string, stack []*Schema) {
+	s = s.Alias()
+	if s == nil || inStack(stack, s) {
+		return
+	}
+	stack = append(stack, s)
+
+	required := map[string]bool{}
+	for _, r := range s.Required {
+		required[r] = true
+	}
+	for name, p := range s.Properties {
+		r := p.Alias()
+		if r == nil {
+			continue
+		}
+		path := name
+		if prefix != "" {
+			path = prefix + "." + name
+		}
+		f[path] = fieldShape{
+			types:    r.Type,
+			format:   r.Format,
+			enum:     enumStrings(r.Enum),
+			required: required[name],
+		}
+		f.add(r, path, stack)
+		for _, item := range r.Items {
+			f.add(item, path+"[]", stack)
+		}
+	}
+}
+
+func enumStrings(enum []interface{}) []string {
+	rs := []string{}
+	for _, e := range enum {
+		b, _ := json.Marshal(e)
+		rs = append(rs, string(b))
+	}
+	return rs
+}
+
+// DiffAPI returns the changes from old to new.
+func DiffAPI(old, new APIShape) []Change {
+	changes := []Change{}
+	for _, id := range unionKeys(old, new) {
+		o, inOld := old[id]
+		n, inNew := new[id]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Breaking: true, Resource: id, Message: "resource removed"})
+		case !inOld:
+			changes = append(changes, Change{Resource: id, Message: "resource added"})
+		default:
+			changes = append(changes, diffResource(id, o, n)...)
+		}
+	}
+	return changes
+}
+
+func diffResource(id string, old, new resourceShape) []Change {
+	changes := diffFields(old.fields, new.fields, false)
+	for i := range changes {
+		changes[i].Resource = id
+	}
+
+	keys := []string{}
+	for k := range old.links {
+		keys = append(keys, k)
+	}
+	for k := range new.links {
+		if _, ok := old.links[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		o, inOld := old.links[k]
+		n, inNew := new.links[k]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{Breaking: true, Resource: id, Link: k, Message: "link removed"})
+		case !inOld:
+			changes = append(changes, Change{Resource: id, Link: k, Message: "link added"})
+		default:
+			cs := append(diffFields(o.request, n.request, true), diffFields(o.response, n.response, false)...)
+			for i := range cs {
+				cs[i].Resource = id
+				cs[i].Link = k
+			}
+			changes = append(changes, cs...)
+		}
+	}
+	return changes
+}
+
+// diffFields compares the fields of a request if request is true, or the
+// fields of a response.
+func diffFields(old, new fieldsShape, request bool) []Change {
+	kind := "response field"
+	if request {
+		kind = "request field"
+	}
+
+	changes := []Change{}
+	for _, path := range unionFieldKeys(old, new) {
+		o, inOld := old[path]
+		n, inNew := new[path]
+		switch {
+		case !inNew:
+			changes = append(changes, Change{
+				Breaking: !request,
+				Message:  fmt.Sprintf("%v %q removed", kind, path),
+			})
+		case !inOld:
+			if request && n.required {
+				changes = append(changes, Change{
+					Breaking: true,
+					Message:  fmt.Sprintf("required %v %q added", kind, path),
+				})
+			} else {
+				changes = append(changes, Change{Message: fmt.Sprintf("%v %q added", kind, path)})
+			}
+		default:
+			changes = append(changes, diffField(kind, path, o, n, request)...)
+		}
+	}
+	return changes
+}
+
+func diffField(kind, path string, old, new fieldShape, request bool) []Change {
+	changes := []Change{}
+	if !reflect.DeepEqual(old.types, new.types) {
+		changes = append(changes, Change{
+			Breaking: true,
+			Message:  fmt.Sprintf("%v %q type changed from %v to %v", kind, path, typeString(old.types), typeString(new.types)),
+		})
+	}
+	if old.format != new.format {
+		changes = append(changes, Change{
+			Breaking: true,
+			Message:  fmt.Sprintf("%v %q format changed from %q to %q", kind, path, old.format, new.format),
+		})
+	}
+	// a request field which becomes required breaks the clients which do
+	// not send it, and a response field which becomes optional breaks the
+	// clients which rely on it.
+	switch {
+	case !old.required && new.required:
+		changes = append(changes, Change{
+			Breaking: request,
+			Message:  fmt.Sprintf("%v %q became required", kind, path),
+		})
+	case old.required && !new.required:
+		changes = append(changes, Change{
+			Breaking: !request,
+			Message:  fmt.Sprintf("%v %q became optional", kind, path),
+		})
+	}
+	if len(old.enum) == 0 && len(new.enum) > 0 {
+		changes = append(changes, Change{
+			Breaking: request,
+			Message:  fmt.Sprintf("%v %q enum narrowed to %v", kind, path, strings.Join(new.enum, ", ")),
+		})
+	} else if len(old.enum) > 0 && len(new.enum) == 0 {
+		// clients may not handle the values which were not in the enum.
+		changes = append(changes, Change{
+			Breaking: !request,
+			Message:  fmt.Sprintf("%v %q enum removed", kind, path),
+		})
+	} else if removed := subtract(old.enum, new.enum); len(removed) > 0 && len(new.enum) > 0 {
+		// clients may send the removed values.
+		changes = append(changes, Change{
+			Breaking: request,
+			Message:  fmt.Sprintf("%v %q enum narrowed, removed %v", kind, path, strings.Join(removed, ", ")),
+		})
+	}
+	if added := subtract(new.enum, old.enum); len(added) > 0 && len(old.enum) > 0 {
+		// clients may not handle the added values.
+		changes = append(changes, Change{
+			Breaking: !request,
+			Message:  fmt.Sprintf("%v %q enum widened, added %v", kind, path, strings.Join(added, ", ")),
+		})
+	}
+	return changes
+}
+
+func typeString(types []string) string {
+	if len(types) == 0 {
+		return "none"
+	}
+	return strings.Join(types, "|")
+}
+
+// subtract returns the values in a which are not in b.
+func subtract(a, b []string) []string {
+	rs := []string{}
+	for _, v := range a {
+		found := false
+		for _, w := range b {
+			if v == w {
+				found = true
+				break
+			}
+		}
+		if !found {
+			rs = append(rs, v)
+		}
+	}
+	return rs
+}
+
+func unionKeys(a, b APIShape) []string {
+	keys := []string{}
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func unionFieldKeys(a, b fieldsShape) []string {
+	keys := []string{}
+	for k := range a {
+		keys = append(keys, k)
+	}
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
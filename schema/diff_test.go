@@ -0,0 +1,179 @@
+package schema
+
+import "testing"
+
+func TestDiffAPI(t *testing.T) {
+	var oldJSON = `{
+		"id": "article",
+		"definitions": {
+			"status": {
+				"type": "string",
+				"enum": ["draft", "published", "archived"]
+			}
+		},
+		"properties": {
+			"id": {"type": "integer"},
+			"title": {"type": "string"},
+			"body": {"type": "string"}
+		},
+		"links": [
+			{
+				"href": "/articles",
+				"method": "POST",
+				"schema": {
+					"properties": {
+						"title": {"type": "string"},
+						"status": {"$ref": "#/definitions/status"}
+					},
+					"required": ["title"]
+				}
+			},
+			{
+				"href": "/articles/{id}",
+				"method": "DELETE"
+			}
+		]
+	}`
+	var newJSON = `{
+		"id": "article",
+		"definitions": {
+			"status": {
+				"type": "string",
+				"enum": ["draft", "published"]
+			}
+		},
+		"properties": {
+			"id": {"type": "string"},
+			"title": {"type": "string"},
+			"tags": {"type": "array"}
+		},
+		"links": [
+			{
+				"href": "/articles",
+				"method": "POST",
+				"schema": {
+					"properties": {
+						"title": {"type": "string"},
+						"status": {"$ref": "#/definitions/status"},
+						"category": {"type": "string"}
+					},
+					"required": ["title", "category"]
+				}
+			},
+			{
+				"href": "/articles",
+				"method": "GET"
+			}
+		]
+	}`
+
+	o, err := NewSchemaFromBytes([]byte(oldJSON), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := NewAPIShape(SchemaSlice{*o})
+	n, err := NewSchemaFromBytes([]byte(newJSON), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	new := NewAPIShape(SchemaSlice{*n})
+
+	expected := []string{
+		`[breaking] article: response field "body" removed`,
+		`[breaking] article: response field "id" type changed from integer to string`,
+		`[non-breaking] article: response field "tags" added`,
+		`[breaking] article DELETE /articles/{id}: link removed`,
+		`[non-breaking] article GET /articles: link added`,
+		`[breaking] article POST /articles: required request field "category" added`,
+		`[breaking] article POST /articles: request field "status" enum narrowed, removed "archived"`,
+	}
+	changes := DiffAPI(old, new)
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %v changes. but %v", len(expected), changes)
+	}
+	for i, c := range changes {
+		if c.String() != expected[i] {
+			t.Errorf("expected %v. but %v", expected[i], c)
+		}
+	}
+	if !HasBreaking(changes) {
+		t.Error("breaking change is not detected")
+	}
+}
+
+func TestDiffAPIResponseFields(t *testing.T) {
+	var oldJSON = `{
+		"id": "diff-response-article",
+		"properties": {
+			"id": {"type": "integer"},
+			"status": {"type": "string", "enum": ["draft", "published"]},
+			"kind": {"type": "string", "enum": ["post"]},
+			"title": {"type": "string"}
+		},
+		"required": ["id"],
+		"links": [
+			{
+				"href": "/articles",
+				"method": "POST",
+				"schema": {
+					"properties": {
+						"title": {"type": "string"},
+						"kind": {"type": "string", "enum": ["post"]}
+					},
+					"required": ["title"]
+				}
+			}
+		]
+	}`
+	var newJSON = `{
+		"id": "diff-response-article",
+		"properties": {
+			"id": {"type": "integer"},
+			"status": {"type": "string", "enum": ["draft", "published", "archived"]},
+			"kind": {"type": "string"},
+			"title": {"type": "string"}
+		},
+		"required": ["title"],
+		"links": [
+			{
+				"href": "/articles",
+				"method": "POST",
+				"schema": {
+					"properties": {
+						"title": {"type": "string"},
+						"kind": {"type": "string"}
+					}
+				}
+			}
+		]
+	}`
+
+	o, err := NewSchemaFromBytes([]byte(oldJSON), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := NewAPIShape(SchemaSlice{*o})
+	n, err := NewSchemaFromBytes([]byte(newJSON), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	new := NewAPIShape(SchemaSlice{*n})
+
+	expected := []string{
+		`[breaking] diff-response-article: response field "id" became optional`,
+		`[breaking] diff-response-article: response field "kind" enum removed`,
+		`[breaking] diff-response-article: response field "status" enum widened, added "archived"`,
+		`[non-breaking] diff-response-article: response field "title" became required`,
+		`[non-breaking] diff-response-article POST /articles: request field "kind" enum removed`,
+		`[non-breaking] diff-response-article POST /articles: request field "title" became optional`,
+	}
+	changes := DiffAPI(old, new)
+	if len(changes) != len(expected) {
+		t.Fatalf("expected %v changes. but %v", len(expected), changes)
+	}
+	for i, c := range changes {
+		if c.String() != expected[i] {
+			t.Errorf("expected %v. but %v", expected[i], c)
+		}
+	}
+}
@@ -0,0 +1,489 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/ghodss/yaml"
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// schemaKeyOrder is the canonical order of the keys of a schema.
+// Other keys follow in alphabetical order.
+var schemaKeyOrder = []string{
+	"$schema",
+	"id",
+	"title",
+	"description",
+	"type",
+	"definitions",
+	"links",
+	"properties",
+	"required",
+}
+
+// linkKeyOrder is the canonical order of the keys of a link description.
+var linkKeyOrder = []string{
+	"title",
+	"description",
+	"href",
+	"method",
+	"rel",
+	"encType",
+	"schema",
+	"targetSchema",
+}
+
+// ReadFile reads a yaml or json file into a map.
+func ReadFile(path string, info os.FileInfo) (map[string]interface{}, error) {
+	isJSON := isExtJSONFile(info)
+	isYAML := isExtYaml(info)
+
+	if !isJSON && !isYAML {
+		return nil, fmt.Errorf("%v is not support file format", info.Name())
+	}
+
+	rs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var d map[string]interface{}
+	switch {
+	case isJSON:
+		if err := json.Unmarshal(rs, &d); err != nil {
+			return nil, err
+		}
+	case isYAML:
+		if err := yaml.Unmarshal(rs, &d); err != nil {
+			return nil, err
+		}
+	}
+	return d, nil
+}
+
+// YamlFileToJson reads a yaml or json file as json. The keys of the
+// objects are kept in the order of the file.
+func YamlFileToJson(path string, info os.FileInfo) ([]byte, error) {
+	js, _, err := readSource(path, info)
+	return js, err
+}
+
+// readSource reads a yaml or json file as json, and the source of it. The
+// errors have the positions in the file.
+func readSource(path string, info os.FileInfo) ([]byte, *source, error) {
+	isJSON := isExtJSONFile(info)
+	isYAML := isExtYaml(info)
+
+	if !isJSON && !isYAML {
+		return nil, nil, fmt.Errorf("%v is not support file format", info.Name())
+	}
+
+	rs, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	w := bytes.NewBuffer([]byte{})
+	var src *source
+	switch {
+	case isJSON:
+		var d map[string]interface{}
+		if err := json.Unmarshal(rs, &d); err != nil {
+			return nil, nil, jsonError(path, rs, err)
+		}
+		if err := json.Indent(w, rs, "", "  "); err != nil {
+			return nil, nil, jsonError(path, rs, err)
+		}
+		// the positions are of the file, not of the indented json.
+		if src, err = newSource(rs); err != nil {
+			return nil, nil, jsonError(path, rs, err)
+		}
+	case isYAML:
+		// the json and the positions are read from the same nodes.
+		var doc yamlv3.Node
+		if err := yamlv3.Unmarshal(rs, &doc); err != nil {
+			return nil, nil, yamlError(path, err)
+		}
+		if len(doc.Content) == 0 || doc.Content[0].ShortTag() == "!!null" {
+			w.WriteString("{}")
+		} else if root := doc.Content[0]; root.Kind != yamlv3.MappingNode {
+			return nil, nil, &Error{Pos: Position{File: path, Line: root.Line, Column: root.Column}, Message: "the document is not a mapping"}
+		} else if err := writeJSONNode(w, &doc, ""); err != nil {
+			return nil, nil, &Error{Pos: Position{File: path}, Message: err.Error()}
+		}
+		if src, err = newSource(w.Bytes()); err != nil {
+			return nil, nil, &Error{Pos: Position{File: path}, Message: err.Error()}
+		}
+		src.positions = yamlPositions(&doc)
+	}
+	src.file = path
+	return w.Bytes(), src, nil
+}
+
+// FormatYAML returns d as yaml in the canonical form.
+func FormatYAML(d map[string]interface{}) ([]byte, error) {
+	var n yamlv3.Node
+	if err := n.Encode(d); err != nil {
+		return nil, err
+	}
+	canonicalSchemaNode(&n)
+	return encodeYAML(&n)
+}
+
+// FormatYAMLSource returns yaml document p in the canonical form. The
+// comments and the styles of the scalars of p are kept.
+func FormatYAMLSource(p []byte) ([]byte, error) {
+	var doc yamlv3.Node
+	if err := yamlv3.Unmarshal(p, &doc); err != nil {
+		return nil, err
+	}
+	if doc.Kind != yamlv3.DocumentNode || len(doc.Content) == 0 {
+		return nil, fmt.Errorf("empty document")
+	}
+	root := doc.Content[0]
+	// the comment at the top of the file stays at the top.
+	if root.Kind == yamlv3.MappingNode && len(root.Content) > 0 && doc.HeadComment == "" {
+		doc.HeadComment = root.Content[0].HeadComment
+		root.Content[0].HeadComment = ""
+	}
+	canonicalSchemaNode(root)
+	return encodeYAML(&doc)
+}
+
+// encodeYAML returns n as a yaml document indented by 2 spaces.
+func encodeYAML(n *yamlv3.Node) ([]byte, error) {
+	w := bytes.NewBufferString("---\n")
+	e := yamlv3.NewEncoder(w)
+	e.SetIndent(2)
+	if err := e.Encode(n); err != nil {
+		return nil, err
+	}
+	if err := e.Close(); err != nil {
+		return nil, err
+	}
+	return w.Bytes(), nil
+}
+
+// FormatJSON returns d as json in the canonical form.
+func FormatJSON(d map[string]interface{}) ([]byte, error) {
+	var n yamlv3.Node
+	if err := n.Encode(d); err != nil {
+		return nil, err
+	}
+	return formatJSONNode(&n)
+}
+
+// FormatJSONSource returns json document p in the canonical form. The
+// properties and definitions are kept in the order of p.
+func FormatJSONSource(p []byte) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	n, err := jsonNode(d)
+	if err != nil {
+		return nil, jsonError("", p, err)
+	}
+	if _, err := d.Token(); err != io.EOF {
+		return nil, fmt.Errorf("invalid data after the document")
+	}
+	return formatJSONNode(n)
+}
+
+func formatJSONNode(n *yamlv3.Node) ([]byte, error) {
+	canonicalSchemaNode(n)
+	w := bytes.NewBuffer([]byte{})
+	if err := writeJSONNode(w, n, ""); err != nil {
+		return nil, err
+	}
+	w.WriteString("\n")
+	return w.Bytes(), nil
+}
+
+// jsonNode reads the next json value from d as a yaml node, keeping the
+// order of the members of the objects.
+func jsonNode(d *json.Decoder) (*yamlv3.Node, error) {
+	t, err := d.Token()
+	if err != nil {
+		return nil, err
+	}
+	switch t := t.(type) {
+	case json.Delim:
+		n := &yamlv3.Node{Kind: yamlv3.SequenceNode, Tag: "!!seq"}
+		if t == '{' {
+			n = &yamlv3.Node{Kind: yamlv3.MappingNode, Tag: "!!map"}
+		}
+		for d.More() {
+			if n.Kind == yamlv3.MappingNode {
+				k, err := d.Token()
+				if err != nil {
+					return nil, err
+				}
+				key, ok := k.(string)
+				if !ok {
+					return nil, fmt.Errorf("invalid key %v", k)
+				}
+				n.Content = append(n.Content, &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: key})
+			}
+			c, err := jsonNode(d)
+			if err != nil {
+				return nil, err
+			}
+			n.Content = append(n.Content, c)
+		}
+		// the end of the object or the array.
+		if _, err := d.Token(); err != nil {
+			return nil, err
+		}
+		return n, nil
+	case string:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!str", Value: t}, nil
+	case json.Number:
+		tag := "!!int"
+		if strings.ContainsAny(t.String(), ".eE") {
+			tag = "!!float"
+		}
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: tag, Value: t.String()}, nil
+	case bool:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!bool", Value: strconv.FormatBool(t)}, nil
+	default:
+		return &yamlv3.Node{Kind: yamlv3.ScalarNode, Tag: "!!null", Value: "null"}, nil
+	}
+}
+
+// canonicalSchemaNode orders the keys of schema n recursively.
+func canonicalSchemaNode(n *yamlv3.Node) {
+	if n.Kind != yamlv3.MappingNode {
+		canonicalNode(n)
+		return
+	}
+	orderMapping(n, schemaKeyOrder)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		v := n.Content[i+1]
+		switch n.Content[i].Value {
+		case "definitions", "properties", "patternProperties":
+			canonicalSchemaMapNode(v)
+		case "items", "additionalProperties", "not", "allOf", "anyOf", "oneOf":
+			canonicalSchemaValueNode(v)
+		case "links":
+			canonicalLinksNode(v)
+		default:
+			canonicalNode(v)
+		}
+	}
+}
+
+// canonicalSchemaMapNode orders the schemas of a map of named schemas,
+// which are kept in the order of the source.
+func canonicalSchemaMapNode(n *yamlv3.Node) {
+	if n.Kind != yamlv3.MappingNode {
+		canonicalNode(n)
+		return
+	}
+	for i := 1; i < len(n.Content); i += 2 {
+		canonicalSchemaValueNode(n.Content[i])
+	}
+}
+
+// canonicalSchemaValueNode orders a schema or a list of schemas.
+func canonicalSchemaValueNode(n *yamlv3.Node) {
+	switch n.Kind {
+	case yamlv3.MappingNode:
+		canonicalSchemaNode(n)
+	case yamlv3.SequenceNode:
+		for _, c := range n.Content {
+			canonicalSchemaValueNode(c)
+		}
+	}
+}
+
+func canonicalLinksNode(n *yamlv3.Node) {
+	if n.Kind != yamlv3.SequenceNode {
+		canonicalNode(n)
+		return
+	}
+	for _, link := range n.Content {
+		if link.Kind != yamlv3.MappingNode {
+			canonicalNode(link)
+			continue
+		}
+		orderMapping(link, linkKeyOrder)
+		for i := 0; i+1 < len(link.Content); i += 2 {
+			v := link.Content[i+1]
+			switch link.Content[i].Value {
+			case "schema", "targetSchema":
+				canonicalSchemaValueNode(v)
+			default:
+				canonicalNode(v)
+			}
+		}
+	}
+}
+
+// canonicalNode orders the keys of mappings in n alphabetically.
+func canonicalNode(n *yamlv3.Node) {
+	switch n.Kind {
+	case yamlv3.MappingNode:
+		orderMapping(n, nil)
+		for i := 1; i < len(n.Content); i += 2 {
+			canonicalNode(n.Content[i])
+		}
+	case yamlv3.SequenceNode:
+		for _, c := range n.Content {
+			canonicalNode(c)
+		}
+	}
+}
+
+// orderMapping orders the members of mapping n, the keys in order first
+// and the others alphabetically. The comments move with their members.
+func orderMapping(n *yamlv3.Node, order []string) {
+	rank := func(key string) int {
+		for i, k := range order {
+			if k == key {
+				return i
+			}
+		}
+		return len(order)
+	}
+	members := make([][2]*yamlv3.Node, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		members = append(members, [2]*yamlv3.Node{n.Content[i], n.Content[i+1]})
+	}
+	sort.SliceStable(members, func(i, j int) bool {
+		ki, kj := members[i][0].Value, members[j][0].Value
+		if ri, rj := rank(ki), rank(kj); ri != rj {
+			return ri < rj
+		}
+		return rank(ki) == len(order) && ki < kj
+	})
+	for i, m := range members {
+		n.Content[2*i], n.Content[2*i+1] = m[0], m[1]
+	}
+}
+
+// writeJSONNode writes yaml node n as indented json.
+func writeJSONNode(w *bytes.Buffer, n *yamlv3.Node, indent string) error {
+	// an alias can refer to its ancestor.
+	if len(indent) > 200 {
+		return fmt.Errorf("line %v: too deeply nested", n.Line)
+	}
+	next := indent + "  "
+	switch n.Kind {
+	case yamlv3.DocumentNode:
+		if len(n.Content) == 0 {
+			w.WriteString("null")
+			return nil
+		}
+		return writeJSONNode(w, n.Content[0], indent)
+	case yamlv3.AliasNode:
+		return writeJSONNode(w, n.Alias, indent)
+	case yamlv3.MappingNode:
+		members := mappingMembers(n, 0)
+		if len(members) == 0 {
+			w.WriteString("{}")
+			return nil
+		}
+		w.WriteString("{\n")
+		for i := 0; i+1 < len(members); i += 2 {
+			k, err := json.Marshal(members[i].Value)
+			if err != nil {
+				return err
+			}
+			w.WriteString(next)
+			w.Write(k)
+			w.WriteString(": ")
+			if err := writeJSONNode(w, members[i+1], next); err != nil {
+				return err
+			}
+			if i+2 < len(members) {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(indent + "}")
+	case yamlv3.SequenceNode:
+		if len(n.Content) == 0 {
+			w.WriteString("[]")
+			return nil
+		}
+		w.WriteString("[\n")
+		for i, c := range n.Content {
+			w.WriteString(next)
+			if err := writeJSONNode(w, c, next); err != nil {
+				return err
+			}
+			if i < len(n.Content)-1 {
+				w.WriteString(",")
+			}
+			w.WriteString("\n")
+		}
+		w.WriteString(indent + "]")
+	default:
+		var v interface{} = n.Value
+		switch n.ShortTag() {
+		case "!!null":
+			v = nil
+		case "!!bool", "!!int", "!!float":
+			if err := n.Decode(&v); err != nil {
+				return err
+			}
+		}
+		b, err := json.Marshal(v)
+		if err != nil {
+			return err
+		}
+		w.Write(b)
+	}
+	return nil
+}
+
+// mappingMembers returns the keys and the values of mapping n, with the
+// members of the mappings merged by "<<". The members of n override the
+// merged ones.
+func mappingMembers(n *yamlv3.Node, depth int) []*yamlv3.Node {
+	for n.Kind == yamlv3.AliasNode && n.Alias != nil {
+		n = n.Alias
+	}
+	if n.Kind != yamlv3.MappingNode || depth > 100 {
+		return nil
+	}
+	own := map[string]bool{}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		own[n.Content[i].Value] = true
+	}
+	members := []*yamlv3.Node{}
+	seen := map[string]bool{}
+	add := func(k, v *yamlv3.Node) {
+		if !seen[k.Value] {
+			members = append(members, k, v)
+			seen[k.Value] = true
+		}
+	}
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		k, v := n.Content[i], n.Content[i+1]
+		if k.Value != "<<" || k.ShortTag() != "!!merge" {
+			add(k, v)
+			continue
+		}
+		merged := []*yamlv3.Node{v}
+		if v.Kind == yamlv3.SequenceNode {
+			merged = v.Content
+		}
+		for _, m := range merged {
+			mm := mappingMembers(m, depth+1)
+			for j := 0; j+1 < len(mm); j += 2 {
+				if !own[mm[j].Value] {
+					add(mm[j], mm[j+1])
+				}
+			}
+		}
+	}
+	return members
+}
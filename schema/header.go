@@ -0,0 +1,35 @@
+package schema
+
+// Header is a documented header of a request or a response.
+type Header struct {
+	Name        string
+	Description string
+	Example     string
+	Required    bool
+}
+
+// parseHeaders parses the headers of a link, such as
+// [{name: ETag, description: "version of the resource", example: "\"1\""}].
+func parseHeaders(data interface{}) []*Header {
+	list, ok := data.([]interface{})
+	if !ok {
+		return []*Header{}
+	}
+	headers := []*Header{}
+	for _, v := range list {
+		d, ok := v.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		h := &Header{
+			Name:        String(d, "name"),
+			Description: String(d, "description"),
+			Example:     String(d, "example"),
+		}
+		h.Required, _ = d["required"].(bool)
+		if h.Name != "" {
+			headers = append(headers, h)
+		}
+	}
+	return headers
+}
@@ -0,0 +1,57 @@
+package schema
+
+import (
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+var hrefVariable = regexp.MustCompile(`\{([^}]*)\}`)
+
+// HrefPart is a literal string or a variable of href template.
+type HrefPart struct {
+	Literal string
+	Param   string
+}
+
+// HrefParts splits the href of l into literals and variables.
+// A variable such as "{(%23%2Fdefinitions%2Fid)}" is named by the last
+// segment of the reference, "id".
+func (l *LinkDescription) HrefParts() []HrefPart {
+	parts := []HrefPart{}
+	rest := l.Href
+	for _, m := range hrefVariable.FindAllStringSubmatchIndex(l.Href, -1) {
+		offset := len(l.Href) - len(rest)
+		if lit := rest[0 : m[0]-offset]; lit != "" {
+			parts = append(parts, HrefPart{Literal: lit})
+		}
+		parts = append(parts, HrefPart{Param: hrefParamName(l.Href[m[2]:m[3]])})
+		rest = l.Href[m[1]:]
+	}
+	if rest != "" {
+		parts = append(parts, HrefPart{Literal: rest})
+	}
+	return parts
+}
+
+// HrefParams returns the names of the variables of the href of l.
+func (l *LinkDescription) HrefParams() []string {
+	params := []string{}
+	for _, p := range l.HrefParts() {
+		if p.Param != "" {
+			params = append(params, p.Param)
+		}
+	}
+	return params
+}
+
+func hrefParamName(s string) string {
+	if u, err := url.QueryUnescape(s); err == nil {
+		s = u
+	}
+	s = strings.Trim(s, "()")
+	if n := strings.LastIndex(s, "/"); n >= 0 {
+		s = s[n+1:]
+	}
+	return s
+}
@@ -0,0 +1,203 @@
+package schema
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	yamlv3 "gopkg.in/yaml.v3"
+)
+
+// Position is a position in a source file. Line and Column start at 1,
+// and are 0 if they are not known.
+type Position struct {
+	File   string
+	Line   int
+	Column int
+}
+
+// String returns the position such as "user.yml:12:3".
+func (p Position) String() string {
+	s := p.File
+	if p.Line > 0 {
+		if s != "" {
+			s += ":"
+		}
+		s += strconv.Itoa(p.Line)
+		if p.Column > 0 {
+			s += ":" + strconv.Itoa(p.Column)
+		}
+	}
+	return s
+}
+
+// Error is an error of a node of a source file.
+type Error struct {
+	Pos Position
+	// Pointer is the json pointer of the node.
+	Pointer string
+	Message string
+}
+
+func (e *Error) Error() string {
+	if pos := e.Pos.String(); pos != "" {
+		return pos + ": " + e.Message
+	}
+	return e.Message
+}
+
+// ErrorList is a list of errors, one per line.
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	lines := make([]string, len(l))
+	for i, e := range l {
+		lines[i] = e.Error()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Add adds err to l. The errors of an ErrorList are added one by one.
+func (l *ErrorList) Add(err error) {
+	switch err := err.(type) {
+	case nil:
+	case ErrorList:
+		*l = append(*l, err...)
+	case *Error:
+		*l = append(*l, err)
+	default:
+		*l = append(*l, &Error{Message: err.Error()})
+	}
+}
+
+// Err returns l as an error, or nil if l is empty.
+func (l ErrorList) Err() error {
+	if len(l) == 0 {
+		return nil
+	}
+	return l
+}
+
+// lineIndex is the offsets of the lines of a file.
+type lineIndex []int
+
+func newLineIndex(p []byte) lineIndex {
+	lines := lineIndex{0}
+	for i, c := range p {
+		if c == '\n' {
+			lines = append(lines, i+1)
+		}
+	}
+	return lines
+}
+
+// position returns the position of offset.
+func (lines lineIndex) position(offset int) Position {
+	n := sort.Search(len(lines), func(i int) bool { return lines[i] > offset }) - 1
+	if n < 0 {
+		n = 0
+	}
+	if offset < lines[n] {
+		offset = lines[n]
+	}
+	return Position{Line: n + 1, Column: offset - lines[n] + 1}
+}
+
+// jsonError returns err of json.Unmarshal of p in file with its position.
+func jsonError(file string, p []byte, err error) error {
+	pos := Position{File: file}
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		// Offset is after the invalid character.
+		pos = newLineIndex(p).position(int(e.Offset) - 1)
+	case *json.UnmarshalTypeError:
+		pos = newLineIndex(p).position(int(e.Offset))
+	}
+	pos.File = file
+	return &Error{Pos: pos, Message: err.Error()}
+}
+
+var yamlErrorLine = regexp.MustCompile(`^yaml: line (\d+): (.*)$`)
+
+// yamlError returns err of parsing yaml file with its position.
+func yamlError(file string, err error) error {
+	if m := yamlErrorLine.FindStringSubmatch(err.Error()); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return &Error{Pos: Position{File: file, Line: line}, Message: m[2]}
+	}
+	return &Error{Pos: Position{File: file}, Message: err.Error()}
+}
+
+// yamlPositions returns the positions of the nodes of yaml document doc by
+// json pointer. The position of a value of a mapping is the position of its
+// key.
+func yamlPositions(doc *yamlv3.Node) map[string]Position {
+	positions := map[string]Position{}
+	var walk func(n *yamlv3.Node, pointer string, depth int)
+	walk = func(n *yamlv3.Node, pointer string, depth int) {
+		// an alias can refer to its ancestor.
+		if depth > 100 {
+			return
+		}
+		if _, ok := positions[pointer]; !ok {
+			positions[pointer] = Position{Line: n.Line, Column: n.Column}
+		}
+		switch n.Kind {
+		case yamlv3.DocumentNode:
+			for _, c := range n.Content {
+				walk(c, pointer, depth+1)
+			}
+		case yamlv3.MappingNode:
+			for i := 0; i+1 < len(n.Content); i += 2 {
+				k, v := n.Content[i], n.Content[i+1]
+				if k.Value == "<<" {
+					// the members of a merged mapping are of n.
+					walk(v, pointer, depth+1)
+					continue
+				}
+				child := pointer + "/" + escapePointer(k.Value)
+				if _, ok := positions[child]; !ok {
+					positions[child] = Position{Line: k.Line, Column: k.Column}
+				}
+				walk(v, child, depth+1)
+			}
+		case yamlv3.SequenceNode:
+			for i, c := range n.Content {
+				walk(c, pointer+"/"+strconv.Itoa(i), depth+1)
+			}
+		case yamlv3.AliasNode:
+			if n.Alias != nil {
+				walk(n.Alias, pointer, depth+1)
+			}
+		}
+	}
+	walk(doc, "", 0)
+	return positions
+}
+
+// errorf returns the error of the node at pointer of src.
+func (src *source) errorf(pointer, format string, args ...interface{}) *Error {
+	return &Error{Pos: src.position(pointer), Pointer: pointer, Message: fmt.Sprintf(format, args...)}
+}
+
+// position returns the position of the node at pointer, or of its nearest
+// ancestor which has a position.
+func (src *source) position(pointer string) Position {
+	if src == nil {
+		return Position{}
+	}
+	for {
+		if pos, ok := src.positions[pointer]; ok {
+			pos.File = src.file
+			return pos
+		}
+		n := strings.LastIndex(pointer, "/")
+		if n < 0 {
+			return Position{File: src.file}
+		}
+		pointer = pointer[0:n]
+	}
+}
@@ -0,0 +1,217 @@
+package schema
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// writeTempFile writes data to name in a temporary directory.
+func writeTempFile(t *testing.T, name, data string) (string, os.FileInfo, func()) {
+	dir, err := ioutil.TempDir("", "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	file := filepath.Join(dir, name)
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return file, info, func() { os.RemoveAll(dir) }
+}
+
+func TestPositionYAML(t *testing.T) {
+	file, info, cleanup := writeTempFile(t, "user.yml", `id: position-yaml-user
+properties:
+  name:
+    type: string
+links:
+- href: /users
+  method: GET
+  schema:
+    properties:
+      q: {}
+`)
+	defer cleanup()
+
+	s, err := NewSchemaFromFile(file, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		pos      Position
+		expected string
+	}{
+		{s.Position(), file + ":1:1"},
+		{s.Properties["name"].Position(), file + ":3:3"},
+		{s.Links[0].Position(), file + ":6:3"},
+		{s.Links[0].Schema.Properties["q"].Position(), file + ":10:7"},
+	}
+	for _, test := range tests {
+		if test.pos.String() != test.expected {
+			t.Errorf("expected %v, but %v", test.expected, test.pos)
+		}
+	}
+}
+
+func TestPositionJSON(t *testing.T) {
+	file, info, cleanup := writeTempFile(t, "user.json", `{
+  "id": "position-json-user",
+  "properties": {
+    "name": {"type": "string"},
+    "id": {
+      "type": "integer"
+    }
+  }
+}
+`)
+	defer cleanup()
+
+	s, err := NewSchemaFromFile(file, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pos := s.Properties["name"].Position().String(); pos != file+":4:5" {
+		t.Errorf("unexpected position %v", pos)
+	}
+	if pos := s.Properties["id"].Position().String(); pos != file+":5:5" {
+		t.Errorf("unexpected position %v", pos)
+	}
+}
+
+func TestReadSourceError(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     string
+		expected string
+	}{
+		{"broken.yml", "id: broken\ntitle: a\n  description: b: c\n", ":3: "},
+		{"list.yml", "- id: list\n", ":1:1: "},
+		{"broken.json", "{\n  \"id\": \"broken\",\n}\n", ":3:1: "},
+	}
+	for _, test := range tests {
+		file, info, cleanup := writeTempFile(t, test.name, test.data)
+		_, err := YamlFileToJson(file, info)
+		cleanup()
+		if err == nil || !strings.HasPrefix(err.Error(), file+test.expected) {
+			t.Errorf("%v: expected an error at %v, but %v", test.name, test.expected, err)
+		}
+	}
+}
+
+func TestReadSourceYAML(t *testing.T) {
+	tests := []struct {
+		data     string
+		expected string
+	}{
+		{"", "{}"},
+		{"# only a comment\n", "{}"},
+		{"base: &base\n  type: string\n  title: base\nname:\n  <<: *base\n  title: name\n",
+			`{"base":{"type":"string","title":"base"},"name":{"type":"string","title":"name"}}`},
+		{"responses:\n  200:\n    ok: true\n", `{"responses":{"200":{"ok":true}}}`},
+	}
+	for _, test := range tests {
+		file, info, cleanup := writeTempFile(t, "user.yml", test.data)
+		js, err := YamlFileToJson(file, info)
+		cleanup()
+		if err != nil {
+			t.Errorf("%q: %v", test.data, err)
+			continue
+		}
+		if !sameJSON(js, test.expected) {
+			t.Errorf("%q: expected %v, but %s", test.data, test.expected, js)
+		}
+	}
+}
+
+// sameJSON returns true if json p and q are the same values.
+func sameJSON(p []byte, q string) bool {
+	var a, b interface{}
+	json.Unmarshal(p, &a)
+	json.Unmarshal([]byte(q), &b)
+	return reflect.DeepEqual(a, b)
+}
+
+func TestValidateFile(t *testing.T) {
+	file, info, cleanup := writeTempFile(t, "user.yml", `id: validate-user
+properties:
+  id:
+    type: 3
+`)
+	defer cleanup()
+
+	err := ValidateFile(file, info)
+	errs, ok := err.(ErrorList)
+	if !ok || len(errs) == 0 {
+		t.Fatalf("expected errors, but %v", err)
+	}
+	for _, e := range errs {
+		if e.Pos.String() != file+":4:5" {
+			t.Errorf("unexpected position of %v", e)
+		}
+	}
+}
+
+// The files are validated against the meta schema of draft 04, which
+// rejects the files gojsonschema compiles without the check.
+func TestValidateFileMetaSchema(t *testing.T) {
+	data := `id: validate-meta-user
+properties:
+  id:
+    type: integer
+required: []
+`
+	file, info, cleanup := writeTempFile(t, "user.yml", data)
+	defer cleanup()
+
+	js, err := YamlFileToJson(file, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := ValidSchema(js); err != nil {
+		t.Fatalf("the schema must compile, but %v", err)
+	}
+	err = ValidateFile(file, info)
+	if errs, ok := err.(ErrorList); !ok || len(errs) != 1 || errs[0].Pos.String() != file+":5:1" {
+		t.Errorf("expected the error of required, but %v", err)
+	}
+}
+
+func TestValidRefs(t *testing.T) {
+	file, info, cleanup := writeTempFile(t, "user.yml", `id: refs-user
+definitions:
+  id:
+    type: integer
+properties:
+  id:
+    $ref: "#/definitions/id"
+  name:
+    $ref: "#/definitions/name"
+  group:
+    $ref: "refs-unknown.json#/definitions/id"
+`)
+	defer cleanup()
+
+	if err := ValidateFile(file, info); err == nil || err.Error() != file+":9:5: unresolved $ref #/definitions/name" {
+		t.Errorf("unexpected error %v", err)
+	}
+
+	s, err := NewSchemaFromFile(file, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		file + ":9:5: unresolved $ref #/definitions/name",
+		file + ":11:5: unresolved $ref refs-unknown.json#/definitions/id",
+	}
+	if err := s.ValidRefs(); err == nil || err.Error() != strings.Join(expected, "\n") {
+		t.Errorf("expected %v, but %v", expected, err)
+	}
+}
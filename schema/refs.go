@@ -0,0 +1,112 @@
+package schema
+
+import "sort"
+
+// ExternalRefs returns the ids of the other schemas which s refers to.
+func (s *Schema) ExternalRefs() []string {
+	ids := map[string]bool{}
+	collectRefs(s.raw, ids)
+	delete(ids, "")
+	delete(ids, s.Id)
+
+	rs := []string{}
+	for id := range ids {
+		rs = append(rs, id)
+	}
+	sort.Strings(rs)
+	return rs
+}
+
+func collectRefs(v interface{}, ids map[string]bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			id, _ := parseReference("", ref)
+			ids[id] = true
+		}
+		for _, value := range v {
+			collectRefs(value, ids)
+		}
+	case []interface{}:
+		for _, value := range v {
+			collectRefs(value, ids)
+		}
+	}
+}
+
+// Position returns the position of s in its source file.
+func (s *Schema) Position() Position {
+	return s.source.position(s.pointer)
+}
+
+// ValidRefs returns the errors of the $refs of s and its subschemas which
+// can not be resolved. The other schemas which s refers to must be parsed
+// before.
+func (s *Schema) ValidRefs() error {
+	return s.refErrors(false).Err()
+}
+
+// refErrors returns the errors of the unresolved $refs of s and its
+// subschemas. If local, only the $refs in the same file are checked.
+func (s *Schema) refErrors(local bool) ErrorList {
+	errs := ErrorList{}
+	s.walk(func(v *Schema) {
+		if v.Ref == "" {
+			return
+		}
+		if id, _ := parseReference(v.Id, v.Ref); local && id != v.Id {
+			return
+		}
+		if v.lookupRef(v.Ref) == nil {
+			errs = append(errs, v.source.errorf(v.pointer+"/$ref", "unresolved $ref %v", v.Ref))
+		}
+	})
+	return errs
+}
+
+// lookupRef returns the schema which ref refers to, or nil. Unlike
+// resolveReference, it does not fall back to s if the other schema is
+// not parsed.
+func (s *Schema) lookupRef(ref string) *Schema {
+	idStr, refStr := parseReference(s.Id, ref)
+	schemasMu.RLock()
+	target, ok := schemas[idStr]
+	schemasMu.RUnlock()
+	if ok {
+		return target.refPool.Get(refStr)
+	}
+	if idStr != s.Id {
+		return nil
+	}
+	return s.refPool.Get(refStr)
+}
+
+// walk calls fn with s and its subschemas.
+func (s *Schema) walk(fn func(*Schema)) {
+	fn(s)
+	for _, name := range s.DefinitionNames() {
+		s.Definitions[name].walk(fn)
+	}
+	for _, name := range s.PropertyNames() {
+		s.Properties[name].walk(fn)
+	}
+	for _, v := range s.Items {
+		v.walk(fn)
+	}
+	for _, v := range s.OneOf {
+		v.walk(fn)
+	}
+	for _, l := range s.Links {
+		if l.hasSchema && l.Schema != nil {
+			l.Schema.walk(fn)
+		}
+		if l.hasTargetSchema && l.TargetSchema != nil {
+			l.TargetSchema.walk(fn)
+		}
+		for _, r := range l.Errors {
+			if r.Schema != nil {
+				r.Schema.walk(fn)
+			}
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package schema
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+// Response is a documented response of a link.
+type Response struct {
+	StatusCode  int
+	Description string
+	// Schema is the body of the response, or nil if the response has no
+	// body.
+	Schema *Schema
+}
+
+// StatusLine returns the status code and its text, such as "201 Created".
+func (r *Response) StatusLine() string {
+	return fmt.Sprintf("%v %v", r.StatusCode, http.StatusText(r.StatusCode))
+}
+
+// Response returns the successful response of l. The status code is
+// "statusCode" of the link, or guessed from its rel.
+func (l *LinkDescription) Response() *Response {
+	r := &Response{StatusCode: l.StatusCode, Schema: l.TargetSchema}
+	if r.StatusCode == 0 {
+		switch l.Rel {
+		case "create":
+			r.StatusCode = http.StatusCreated
+		case "empty":
+			r.StatusCode = http.StatusAccepted
+		case "update", "destroy":
+			r.StatusCode = http.StatusNoContent
+		default:
+			r.StatusCode = http.StatusOK
+		}
+	}
+	if r.StatusCode == http.StatusNoContent || (l.StatusCode == 0 && l.Rel == "empty") {
+		r.Schema = nil
+	}
+	return r
+}
+
+// Responses returns the successful response and the error responses of l.
+func (l *LinkDescription) Responses() []*Response {
+	return append([]*Response{l.Response()}, l.Errors...)
+}
+
+// parseErrors parses "errors" of the nth link, such as
+// [{status: 404, description: "not found", schema: {...}}].
+func (s *Schema) parseErrors(data interface{}, n int) []*Response {
+	list, ok := data.([]interface{})
+	if !ok {
+		return []*Response{}
+	}
+	responses := []*Response{}
+	for i, v := range list {
+		r := &Response{
+			StatusCode:  Int(v, "status"),
+			Description: String(v, "description"),
+		}
+		if d, ok := v.(map[string]interface{}); ok && d["schema"] != nil {
+			refStr := s.appendRefPath(fmt.Sprintf("links[%v]", n), fmt.Sprintf("errors[%v]", i), "schema")
+			r.Schema, _ = s.newChild(d["schema"], refStr, "links", strconv.Itoa(n), "errors", strconv.Itoa(i), "schema")
+		}
+		responses = append(responses, r)
+	}
+	return responses
+}
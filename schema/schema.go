@@ -1,17 +1,24 @@
 package schema
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/url"
 	"os"
+	"strconv"
 	"strings"
+	"sync"
 )
 
 var schemas map[string]*Schema = make(map[string]*Schema, 0)
 
+// schemasMu guards schemas, since files may be parsed concurrently.
+var schemasMu sync.RWMutex
+
 type refPool struct {
+	mu     sync.RWMutex
 	refMap map[string]*Schema
 }
 
@@ -22,15 +29,21 @@ func NewRefPool() *refPool {
 }
 
 func (r *refPool) Get(refStr string) *Schema {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return r.refMap[refStr]
 }
 
 func (r *refPool) Set(refStr string, s *Schema) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	r.refMap[refStr] = s
 }
 
 func (r *refPool) Keys() []string {
-	rs := make([]string, r.Len())
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	rs := make([]string, len(r.refMap))
 	var i int
 	for k, _ := range r.refMap {
 		rs[i] = k
@@ -40,6 +53,8 @@ func (r *refPool) Keys() []string {
 }
 
 func (r *refPool) Len() int {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
 	return len(r.refMap)
 }
 
@@ -51,25 +66,41 @@ type Schema struct {
 	Type        []string
 	Format      string
 	Example     interface{}
+	Enum        []interface{}
+	Required    []string
 	Definitions map[string]*Schema
 	Properties  map[string]*Schema
 
 	Items []*Schema
+	OneOf []*Schema
 	Links []*LinkDescription
 
+	// Group is the section of the resource in the document, from the
+	// "x-group" or "category" keyword.
+	Group string
+
 	Ref string
 
 	CurrentRef string
 	refPool    *refPool
 	parent     *Schema
+	raw        map[string]interface{}
+	// source is the document of the schema, or nil if it is not known.
+	source *source
+	// pointer is the json pointer of the schema in source.
+	pointer string
 }
 
 func NewSchemaFromFile(path string, info os.FileInfo) (*Schema, error) {
-	bytes, err := YamlFileToJson(path, info)
+	bytes, src, err := readSource(path, info)
 	if err != nil {
 		return nil, err
 	}
-	return NewSchemaFromBytes(bytes, "", nil)
+	var dataMap map[string]interface{}
+	if err := json.Unmarshal(bytes, &dataMap); err != nil {
+		return nil, src.errorf("", "%v", err)
+	}
+	return newSchema(dataMap, "#", nil, src, "")
 }
 
 func NewSchemaFromInterface(data interface{}, refStr string, parent *Schema) (*Schema, error) {
@@ -89,12 +120,22 @@ func NewSchemaFromBytes(data []byte, refStr string, parent *Schema) (*Schema, er
 	}
 	var dataMap map[string]interface{}
 	if err := json.Unmarshal(data, &dataMap); err != nil {
+		return nil, jsonError("", data, err)
+	}
+	src, err := newSource(data)
+	if err != nil {
 		return nil, err
 	}
-	return NewSchema(dataMap, refStr, parent)
+	return newSchema(dataMap, refStr, parent, src, "")
 }
 
 func NewSchema(data map[string]interface{}, refStr string, parent *Schema) (*Schema, error) {
+	return newSchema(data, refStr, parent, nil, "")
+}
+
+// newSchema parses data at pointer of src. The order of the properties and
+// the definitions is read from src.
+func newSchema(data map[string]interface{}, refStr string, parent *Schema, src *source, pointer string) (*Schema, error) {
 	if refStr == "" {
 		refStr = "#"
 	}
@@ -107,17 +148,29 @@ func NewSchema(data map[string]interface{}, refStr string, parent *Schema) (*Sch
 		Format:      String(data, "format"),
 		Title:       String(data, "title"),
 		Example:     Interface(data, "example", typeStr),
+		Enum:        Slice(data, "enum"),
+		Required:    StringSlice(data, "required"),
 		Ref:         String(data, "$ref"),
+		Group:       String(data, "x-group"),
 		CurrentRef:  refStr,
 		parent:      parent,
+		raw:         data,
+		source:      src,
+		pointer:     pointer,
+	}
+	if s.Group == "" {
+		s.Group = String(data, "category")
 	}
 	s.Properties = make(map[string]*Schema, 0)
 	s.Definitions = make(map[string]*Schema, 0)
 	s.Items = make([]*Schema, 0)
+	s.OneOf = make([]*Schema, 0)
 	s.Links = make([]*LinkDescription, 0)
 
 	if idStr != "" {
+		schemasMu.Lock()
 		schemas[idStr] = s
+		schemasMu.Unlock()
 	}
 
 	// reference pool
@@ -132,19 +185,33 @@ func NewSchema(data map[string]interface{}, refStr string, parent *Schema) (*Sch
 	s.parseDefinitions(data["definitions"])
 	s.parseLinks(data["links"])
 	s.parseItems(data["items"])
+	s.parseOneOf(data["oneOf"])
 
 	s.refPool.Set(refStr, s)
 
 	return s, nil
 }
 
+// newChild parses data at path from s as a schema.
+func (s *Schema) newChild(data interface{}, refStr string, path ...string) (*Schema, error) {
+	d, ok := data.(map[string]interface{})
+	if !ok {
+		return nil, errors.New("data type is not map[string]interface{}")
+	}
+	pointer := s.pointer
+	for _, p := range path {
+		pointer += "/" + escapePointer(p)
+	}
+	return newSchema(d, refStr, s, s.source, pointer)
+}
+
 func (s *Schema) parseProperties(data interface{}) {
 	properties, ok := data.(map[string]interface{})
 	if !ok {
 		return
 	}
 	for key, property := range properties {
-		prop, err := NewSchemaFromInterface(property, s.appendRefPath("properties", key), s)
+		prop, err := s.newChild(property, s.appendRefPath("properties", key), "properties", key)
 		if err != nil {
 			continue
 		}
@@ -158,7 +225,7 @@ func (s *Schema) parseDefinitions(data interface{}) {
 		return
 	}
 	for key, definition := range definitions {
-		def, err := NewSchemaFromInterface(definition, s.appendRefPath("definitions", key), s)
+		def, err := s.newChild(definition, s.appendRefPath("definitions", key), "definitions", key)
 		if err != nil {
 			continue
 		}
@@ -182,27 +249,41 @@ func (s *Schema) parseLinks(data interface{}) error {
 			return errors.New("parse failed links")
 		}
 		var schema *Schema
-		if v, ok := link["schema"]; ok {
-			schema, _ = NewSchemaFromInterface(v, s.appendRefPath(fmt.Sprintf("links[%v]", i), "schema"), s)
+		_, hasSchema := link["schema"]
+		if hasSchema {
+			schema, _ = s.newChild(link["schema"], s.appendRefPath(fmt.Sprintf("links[%v]", i), "schema"), "links", strconv.Itoa(i), "schema")
 		} else {
 			schema = s
 		}
 		var targetSchema *Schema
-		if v, ok := link["targetSchema"]; ok {
-			targetSchema, _ = NewSchemaFromInterface(v, s.appendRefPath(fmt.Sprintf("links[%v]", i), "targetSchema"), s)
+		_, hasTargetSchema := link["targetSchema"]
+		if hasTargetSchema {
+			targetSchema, _ = s.newChild(link["targetSchema"], s.appendRefPath(fmt.Sprintf("links[%v]", i), "targetSchema"), "links", strconv.Itoa(i), "targetSchema")
 		} else {
 			targetSchema = s
 		}
 
+		_, hasSecurity := link["security"]
 		l := &LinkDescription{
-			Title:        String(link, "title"),
-			Description:  String(link, "description"),
-			Href:         String(link, "href"),
-			Method:       String(link, "method"),
-			Rel:          String(link, "rel"),
-			EncType:      String(link, "encType"),
-			Schema:       schema,
-			TargetSchema: targetSchema,
+			Title:           String(link, "title"),
+			Description:     String(link, "description"),
+			Href:            String(link, "href"),
+			Method:          String(link, "method"),
+			Rel:             String(link, "rel"),
+			EncType:         String(link, "encType"),
+			Schema:          schema,
+			TargetSchema:    targetSchema,
+			StatusCode:      Int(link, "statusCode"),
+			Errors:          s.parseErrors(link["errors"], i),
+			RequestHeaders:  parseHeaders(link["requestHeaders"]),
+			ResponseHeaders: parseHeaders(link["responseHeaders"]),
+			Security:        StringSlice(link, "security"),
+			Scopes:          StringSlice(link, "scopes"),
+
+			hasSchema:       hasSchema,
+			hasTargetSchema: hasTargetSchema,
+			hasSecurity:     hasSecurity,
+			pos:             s.source.position(s.pointer + "/links/" + strconv.Itoa(i)),
 		}
 
 		s.Links = append(s.Links, l)
@@ -212,7 +293,7 @@ func (s *Schema) parseLinks(data interface{}) error {
 }
 
 func (s *Schema) parseItems(data interface{}) error {
-	item, err := NewSchemaFromInterface(data, s.appendRefPath("items"), s)
+	item, err := s.newChild(data, s.appendRefPath("items"), "items")
 	if err != nil {
 		return err
 	}
@@ -220,9 +301,26 @@ func (s *Schema) parseItems(data interface{}) error {
 	return nil
 }
 
+func (s *Schema) parseOneOf(data interface{}) {
+	list, ok := data.([]interface{})
+	if !ok {
+		return
+	}
+	for i, v := range list {
+		one, err := s.newChild(v, s.appendRefPath("oneOf", strconv.Itoa(i)), "oneOf", strconv.Itoa(i))
+		if err != nil {
+			continue
+		}
+		s.OneOf = append(s.OneOf, one)
+	}
+}
+
 func (s *Schema) resolveReference(idStr, refStr string) *Schema {
 	idStr, refStr = parseReference(idStr, refStr)
-	if schema, ok := schemas[idStr]; !ok {
+	schemasMu.RLock()
+	schema, ok := schemas[idStr]
+	schemasMu.RUnlock()
+	if !ok {
 		return s.refPool.Get(refStr)
 	} else {
 		return schema.refPool.Get(refStr)
@@ -299,30 +397,64 @@ func (s *Schema) ExampleInterface() interface{} {
 		return []interface{}{s.Items[0].ExampleInterface()}
 	}
 
-	j := map[string]interface{}{}
-	for key, property := range s.Properties {
+	j := ExampleObject{}
+	for _, key := range s.PropertyNames() {
+		property := s.Properties[key]
 		if property.Ref != "" {
 			refs := s.resolveReference(s.Id, property.Ref)
-			j[key] = refs.ExampleInterface()
+			j = append(j, ExampleMember{key, refs.ExampleInterface()})
 		} else {
-			j[key] = property.ExampleInterface()
+			j = append(j, ExampleMember{key, property.ExampleInterface()})
 		}
 	}
 	return j
 }
 
+// ExampleObject is the example of an object, whose members are in the
+// order of the properties.
+type ExampleObject []ExampleMember
+
+// ExampleMember is a member of ExampleObject.
+type ExampleMember struct {
+	Key   string
+	Value interface{}
+}
+
+// MarshalJSON writes the members of o in order.
+func (o ExampleObject) MarshalJSON() ([]byte, error) {
+	w := bytes.NewBufferString("{")
+	for i, m := range o {
+		if i > 0 {
+			w.WriteString(",")
+		}
+		k, err := json.Marshal(m.Key)
+		if err != nil {
+			return nil, err
+		}
+		v, err := json.Marshal(m.Value)
+		if err != nil {
+			return nil, err
+		}
+		w.Write(k)
+		w.WriteString(":")
+		w.Write(v)
+	}
+	w.WriteString("}")
+	return w.Bytes(), nil
+}
+
+// ExampleGetData returns the example of s as "key=value" pairs of a query
+// in the order of the properties.
 func (s *Schema) ExampleGetData() []string {
-	p := s.ExampleInterface()
-	params, ok := p.(map[string]interface{})
-	if !ok {
+	params, ok := s.ExampleInterface().(ExampleObject)
+	if !ok || len(params) == 0 {
 		return []string{}
 	}
-	val := url.Values{}
-	for k, v := range params {
-		val.Set(k, fmt.Sprintf("%v", v))
+	data := []string{}
+	for _, m := range params {
+		data = append(data, url.QueryEscape(m.Key)+"="+url.QueryEscape(fmt.Sprintf("%v", m.Value)))
 	}
-	e := val.Encode()
-	return strings.Split(e, "&")
+	return data
 }
 
 func (s *Schema) appendRefPath(path ...string) string {
@@ -342,4 +474,48 @@ type LinkDescription struct {
 	EncType      string
 	Schema       *Schema
 	TargetSchema *Schema
+	// StatusCode is the status code of the successful response, or 0 if
+	// it is not documented.
+	StatusCode int
+	// Errors are the documented error responses.
+	Errors []*Response
+	// RequestHeaders are the headers of the request besides the common
+	// headers of meta.
+	RequestHeaders []*Header
+	// ResponseHeaders are the headers of the successful response.
+	ResponseHeaders []*Header
+	// Security is the names of the auth schemes of meta which the link
+	// accepts. An empty list means the link needs no authentication.
+	Security []string
+	// Scopes are the oauth2 scopes the link requires.
+	Scopes []string
+
+	hasSchema       bool
+	hasTargetSchema bool
+	hasSecurity     bool
+	// pos is the position of the link in its source file.
+	pos Position
+}
+
+// Position returns the position of l in its source file.
+func (l *LinkDescription) Position() Position {
+	return l.pos
+}
+
+// HasSchema returns true if the link has its own schema. Schema is the
+// resource of the link otherwise.
+func (l *LinkDescription) HasSchema() bool {
+	return l.hasSchema
+}
+
+// HasSecurity returns true if the link declares its own security instead
+// of the default of meta.
+func (l *LinkDescription) HasSecurity() bool {
+	return l.hasSecurity
+}
+
+// HasTargetSchema returns true if the link has its own targetSchema.
+// TargetSchema is the resource of the link otherwise.
+func (l *LinkDescription) HasTargetSchema() bool {
+	return l.hasTargetSchema
 }
@@ -2,6 +2,10 @@ package schema
 
 import (
 	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
@@ -178,7 +182,7 @@ func TestExampleGetData(t *testing.T) {
 
 	for k, v := range datas {
 		if v != accepts[k] {
-			t.Errorf("accept %v, but TV", v, accepts[k])
+			t.Errorf("accept %v, but %v", accepts[k], v)
 		}
 	}
 }
@@ -292,3 +296,215 @@ const userJSON = `{
   "title": "user",
   "type": "object"
 }`
+
+func TestHrefParts(t *testing.T) {
+	l := &LinkDescription{Href: "/users/{(%23%2Fdefinitions%2Fid)}/articles/{article_id}"}
+	expected := []HrefPart{
+		{Literal: "/users/"},
+		{Param: "id"},
+		{Literal: "/articles/"},
+		{Param: "article_id"},
+	}
+	parts := l.HrefParts()
+	if len(parts) != len(expected) {
+		t.Fatalf("expected %v. but %v", expected, parts)
+	}
+	for i, p := range parts {
+		if p != expected[i] {
+			t.Errorf("expected %v. but %v", expected[i], p)
+		}
+	}
+}
+
+func TestLinkResponses(t *testing.T) {
+	data := `{
+  "id": "response-test",
+  "definitions": {"error": {"properties": {"message": {"type": "string"}}}},
+  "links": [
+    {"href": "/a", "method": "POST", "rel": "create"},
+    {"href": "/a", "method": "PATCH", "rel": "update"},
+    {"href": "/a", "method": "PATCH", "rel": "update", "statusCode": 200,
+     "errors": [{"status": 409, "description": "conflict", "schema": {"$ref": "#/definitions/error"}}]}
+  ]
+}`
+	s, err := NewSchemaFromBytes([]byte(data), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r := s.Links[0].Response(); r.StatusLine() != "201 Created" || r.Schema == nil {
+		t.Errorf("unexpected response %v %v", r.StatusLine(), r.Schema)
+	}
+	if r := s.Links[1].Response(); r.StatusLine() != "204 No Content" || r.Schema != nil {
+		t.Errorf("unexpected response %v %v", r.StatusLine(), r.Schema)
+	}
+
+	rs := s.Links[2].Responses()
+	if len(rs) != 2 {
+		t.Fatalf("expected 2 responses, but %v", len(rs))
+	}
+	if rs[0].StatusCode != 200 || rs[0].Schema == nil {
+		t.Errorf("unexpected response %v %v", rs[0].StatusCode, rs[0].Schema)
+	}
+	if rs[1].StatusCode != 409 || rs[1].Description != "conflict" {
+		t.Errorf("unexpected error %v %v", rs[1].StatusCode, rs[1].Description)
+	}
+	if a := rs[1].Schema.Alias(); a == nil || a.Properties["message"] == nil {
+		t.Errorf("error schema is not resolved")
+	}
+}
+
+func TestLinkHeaders(t *testing.T) {
+	data := `{
+  "links": [
+    {"href": "/a", "method": "PUT",
+     "requestHeaders": [{"name": "If-Match", "description": "version", "example": "\"1\"", "required": true}, {"description": "no name"}],
+     "responseHeaders": [{"name": "ETag", "example": "\"2\""}]}
+  ]
+}`
+	s, err := NewSchemaFromBytes([]byte(data), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	l := s.Links[0]
+	if len(l.RequestHeaders) != 1 {
+		t.Fatalf("expected 1 request header, but %v", len(l.RequestHeaders))
+	}
+	if h := l.RequestHeaders[0]; h.Name != "If-Match" || h.Description != "version" || h.Example != `"1"` || !h.Required {
+		t.Errorf("unexpected header %v", h)
+	}
+	if len(l.ResponseHeaders) != 1 || l.ResponseHeaders[0].Name != "ETag" || l.ResponseHeaders[0].Required {
+		t.Errorf("unexpected headers %v", l.ResponseHeaders)
+	}
+}
+
+func TestSchemaGroup(t *testing.T) {
+	s, err := NewSchemaFromBytes([]byte(`{"id": "group-user", "x-group": "Accounts", "properties": {"name": {}, "id": {}, "age": {}}}`), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Group != "Accounts" {
+		t.Errorf("group is expected Accounts. but %v", s.Group)
+	}
+
+	s, err = NewSchemaFromBytes([]byte(`{"id": "group-article", "category": "Blog"}`), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Group != "Blog" {
+		t.Errorf("group is expected Blog. but %v", s.Group)
+	}
+}
+
+func TestPropertyNames(t *testing.T) {
+	data := `{
+  "id": "order-user",
+  "definitions": {"name": {"type": "string"}, "age": {"type": "integer"}},
+  "properties": {"name": {}, "id": {}, "a/b": {}},
+  "links": [{"href": "/users", "schema": {"properties": {"z": {}, "y": {}}}}]
+}`
+	s, err := NewSchemaFromBytes([]byte(data), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{"name", "id", "a/b"}
+	if names := s.PropertyNames(); strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("expected %v, but %v", expected, names)
+	}
+	if names := s.DefinitionNames(); strings.Join(names, ",") != "name,age" {
+		t.Errorf("unexpected definition names %v", names)
+	}
+	if names := s.Links[0].Schema.PropertyNames(); strings.Join(names, ",") != "z,y" {
+		t.Errorf("unexpected link property names %v", names)
+	}
+
+	// the order of a schema parsed from a map is not known.
+	s, err = NewSchema(map[string]interface{}{
+		"properties": map[string]interface{}{"b": map[string]interface{}{}, "a": map[string]interface{}{}},
+	}, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := s.PropertyNames(); strings.Join(names, ",") != "a,b" {
+		t.Errorf("unexpected property names %v", names)
+	}
+}
+
+func TestYamlFileToJson(t *testing.T) {
+	dir, err := ioutil.TempDir("", "schema")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "user.yml")
+	data := `id: yaml-order-user
+properties:
+  name:
+    type: string
+  id:
+    type: integer
+  tags:
+    type: array
+    items:
+      properties:
+        label: {}
+        color: {}
+`
+	if err := ioutil.WriteFile(file, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+	info, err := os.Stat(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := YamlFileToJson(file, info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := NewSchemaFromBytes(p, "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if names := s.PropertyNames(); strings.Join(names, ",") != "name,id,tags" {
+		t.Errorf("unexpected property names %v in %s", names, p)
+	}
+	if names := s.Properties["tags"].Items[0].PropertyNames(); strings.Join(names, ",") != "label,color" {
+		t.Errorf("unexpected item property names %v", names)
+	}
+}
+
+func TestExampleOrder(t *testing.T) {
+	data := `{
+  "id": "example-order",
+  "properties": {
+    "zeta": {"type": "string", "example": "z"},
+    "id": {"type": "integer", "example": 1},
+    "status": {
+      "properties": {
+        "name": {"type": "string", "example": "active"},
+        "code": {"type": "integer", "example": 2}
+      }
+    }
+  }
+}`
+	s, err := NewSchemaFromBytes([]byte(data), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `{
+  "zeta": "z",
+  "id": 1,
+  "status": {
+    "name": "active",
+    "code": 2
+  }
+}`
+	if example := s.ExampleJSON(); example != expected {
+		t.Errorf("expected %v, but %v", expected, example)
+	}
+	query := s.Properties["status"].ExampleGetData()
+	if len(query) != 2 || query[0] != "name=active" || query[1] != "code=2" {
+		t.Errorf("unexpected query %v", query)
+	}
+}
@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// source is the document which schemas are parsed from.
+type source struct {
+	// keys are the keys of each object in the order of the document, by
+	// the json pointer of the object.
+	keys map[string][]string
+	// file is the path of the document, or "" if it is not a file.
+	file string
+	// positions are the positions of the values in the document, by their
+	// json pointers. The position of a member of an object is the position
+	// of its key.
+	positions map[string]Position
+}
+
+// newSource reads the order of the keys and the positions of the values of
+// json document p.
+func newSource(p []byte) (*source, error) {
+	src := &source{keys: map[string][]string{}, positions: map[string]Position{}}
+	d := json.NewDecoder(bytes.NewReader(p))
+	d.UseNumber()
+	lines := newLineIndex(p)
+	// at returns the position of the next token of d.
+	at := func() Position {
+		off := int(d.InputOffset())
+		for off < len(p) && strings.IndexByte(" \t\r\n,:", p[off]) >= 0 {
+			off++
+		}
+		return lines.position(off)
+	}
+	if err := src.walk(d, at, ""); err != nil {
+		return nil, jsonError("", p, err)
+	}
+	return src, nil
+}
+
+// walk reads the value at pointer from d.
+func (src *source) walk(d *json.Decoder, at func() Position, pointer string) error {
+	if _, ok := src.positions[pointer]; !ok {
+		src.positions[pointer] = at()
+	}
+	t, err := d.Token()
+	if err != nil {
+		return err
+	}
+	delim, ok := t.(json.Delim)
+	if !ok {
+		return nil
+	}
+	switch delim {
+	case '{':
+		keys := []string{}
+		seen := map[string]bool{}
+		for d.More() {
+			pos := at()
+			t, err := d.Token()
+			if err != nil {
+				return err
+			}
+			key, ok := t.(string)
+			if !ok {
+				return fmt.Errorf("invalid key %v", t)
+			}
+			if !seen[key] {
+				keys = append(keys, key)
+				seen[key] = true
+			}
+			child := pointer + "/" + escapePointer(key)
+			if _, ok := src.positions[child]; !ok {
+				src.positions[child] = pos
+			}
+			if err := src.walk(d, at, child); err != nil {
+				return err
+			}
+		}
+		src.keys[pointer] = keys
+	case '[':
+		for i := 0; d.More(); i++ {
+			if err := src.walk(d, at, pointer+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+	}
+	// the end of the object or the array.
+	_, err = d.Token()
+	return err
+}
+
+var pointerEscaper = strings.NewReplacer("~", "~0", "/", "~1")
+
+// escapePointer escapes key as a reference token of a json pointer.
+func escapePointer(key string) string {
+	return pointerEscaper.Replace(key)
+}
+
+// PropertyNames returns the names of the properties in the order of the
+// source, or sorted by name if the order is not known.
+func (s *Schema) PropertyNames() []string {
+	return s.orderedNames(s.Properties, "properties")
+}
+
+// DefinitionNames returns the names of the definitions in the order of the
+// source, or sorted by name if the order is not known.
+func (s *Schema) DefinitionNames() []string {
+	return s.orderedNames(s.Definitions, "definitions")
+}
+
+// orderedNames returns the names of m, which is parsed from key of s. The
+// names which are not in the source follow in the order of the names.
+func (s *Schema) orderedNames(m map[string]*Schema, key string) []string {
+	names := []string{}
+	seen := map[string]bool{}
+	if s.source != nil {
+		for _, name := range s.source.keys[s.pointer+"/"+key] {
+			if _, ok := m[name]; ok {
+				names = append(names, name)
+				seen[name] = true
+			}
+		}
+	}
+	rest := []string{}
+	for name := range m {
+		if !seen[name] {
+			rest = append(rest, name)
+		}
+	}
+	sort.Strings(rest)
+	return append(names, rest...)
+}
@@ -1,15 +1,12 @@
 package schema
 
 import (
-	"encoding/json"
 	"fmt"
-	"io/ioutil"
 	"os"
 	"path"
 	"path/filepath"
+	"strconv"
 	"strings"
-
-	"github.com/ghodss/yaml"
 )
 
 func String(target interface{}, key string) string {
@@ -70,6 +67,19 @@ func StringSlice(target interface{}, key string) []string {
 	return []string{}
 }
 
+func Slice(target interface{}, key string) []interface{} {
+	d, ok := target.(map[string]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+
+	v, ok := d[key].([]interface{})
+	if !ok {
+		return []interface{}{}
+	}
+	return v
+}
+
 func Interface(target interface{}, key string, typeStr []string) interface{} {
 
 	if len(typeStr) != 0 && typeStr[0] == "string" {
@@ -145,32 +155,18 @@ func resourceName(name string) string {
 	return base[0 : len(base)-len(e)]
 }
 
-func YamlFileToJson(path string, info os.FileInfo) ([]byte, error) {
-	isJSON := isExtJSONFile(info)
-	isYAML := isExtYaml(info)
-
-	if !isJSON && !isYAML {
-		return nil, fmt.Errorf("%v is not support file format", info.Name())
-	}
-
-	rs, err := ioutil.ReadFile(path)
-	if err != nil {
-		return nil, err
+func Int(target interface{}, key string) int {
+	d, ok := target.(map[string]interface{})
+	if !ok {
+		return 0
 	}
 
-	var d map[string]interface{}
-	switch {
-	case isJSON:
-		if err := json.Unmarshal(rs, &d); err != nil {
-			return nil, err
-		}
-	case isYAML:
-		if err := yaml.Unmarshal(rs, &d); err != nil {
-			return nil, err
-		}
-	default:
-		return nil, fmt.Errorf("%v is not support file format", info.Name())
+	switch v := d[key].(type) {
+	case float64:
+		return int(v)
+	case string:
+		n, _ := strconv.Atoi(v)
+		return n
 	}
-
-	return json.MarshalIndent(d, "", "  ")
+	return 0
 }
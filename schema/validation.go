@@ -1,6 +1,13 @@
 package schema
 
-import "github.com/xeipuuv/gojsonschema"
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/xeipuuv/gojsonschema"
+)
 
 // ValidSchema returns nil if json is valid.
 func ValidSchema(jsonBytes []byte) error {
@@ -10,3 +17,68 @@ func ValidSchema(jsonBytes []byte) error {
 	}
 	return nil
 }
+
+var (
+	metaSchemaOnce sync.Once
+	metaSchema     *gojsonschema.Schema
+	metaSchemaErr  error
+)
+
+// draft04 returns the meta schema of json schema draft 04.
+func draft04() (*gojsonschema.Schema, error) {
+	metaSchemaOnce.Do(func() {
+		loader := gojsonschema.NewReferenceLoader("http://json-schema.org/draft-04/schema#")
+		metaSchema, metaSchemaErr = gojsonschema.NewSchema(loader)
+	})
+	return metaSchema, metaSchemaErr
+}
+
+// ValidateFile returns nil if the yaml or json file at path is a valid
+// schema. The errors have the positions in the file. The file is validated
+// against the meta schema of draft 04 first, which rejects some files
+// ValidSchema accepts, such as an empty required.
+func ValidateFile(path string, info os.FileInfo) error {
+	js, src, err := readSource(path, info)
+	if err != nil {
+		return err
+	}
+	meta, err := draft04()
+	if err != nil {
+		return err
+	}
+	result, err := meta.Validate(gojsonschema.NewBytesLoader(js))
+	if err != nil {
+		return src.errorf("", "%v", err)
+	}
+	errs := ErrorList{}
+	for _, e := range result.Errors() {
+		pointer := strings.TrimPrefix(e.Context().String("/"), "(root)")
+		errs = append(errs, src.errorf(pointer, "%v", e.Description()))
+	}
+	if len(errs) > 0 {
+		return errs
+	}
+
+	// the unresolved $refs in the file fail the compile below without
+	// their positions.
+	var data map[string]interface{}
+	if err := json.Unmarshal(js, &data); err != nil {
+		return src.errorf("", "%v", err)
+	}
+	s, err := newSchema(data, "#", nil, src, "")
+	if err != nil {
+		return src.errorf("", "%v", err)
+	}
+	if errs := s.refErrors(true); len(errs) > 0 {
+		return errs
+	}
+	// the $refs to the other files are checked by ValidRefs, and can not be
+	// compiled alone.
+	if len(s.ExternalRefs()) > 0 {
+		return nil
+	}
+	if err := ValidSchema(js); err != nil {
+		return src.errorf("", "%v", err)
+	}
+	return nil
+}
@@ -0,0 +1,40 @@
+package snippet
+
+import "strings"
+
+// Curl renders a request as a command of curl.
+type Curl struct{}
+
+func (Curl) Name() string { return "curl" }
+func (Curl) Lang() string { return "bash" }
+
+func (Curl) Generate(r *Request) string {
+	cmd := "curl"
+	switch r.Method {
+	case "GET":
+	case "HEAD":
+		// -X HEAD waits for the body which is never sent.
+		cmd += " -I"
+	default:
+		cmd += " -X " + r.Method
+	}
+	args := []string{cmd + " " + ShellQuote(r.FullURL())}
+	for _, h := range r.Header {
+		args = append(args, "-H "+ShellQuote(h.Name+": "+h.Value))
+	}
+	switch {
+	case r.IsMultipart():
+		// --form-string does not read a value beginning with @ or < as a file.
+		for _, p := range r.Form {
+			args = append(args, "--form-string "+ShellQuote(p.Name+"="+p.Value))
+		}
+	case r.IsForm():
+		for _, p := range r.Form {
+			args = append(args, "--data-urlencode "+ShellQuote(p.Name+"="+p.Value))
+		}
+	case r.JSON != "":
+		args = append(args, "-H "+ShellQuote("Content-Type: "+r.EncType))
+		args = append(args, "-d "+ShellQuote(r.JSON))
+	}
+	return strings.Join(args, " \\\n       ")
+}
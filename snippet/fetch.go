@@ -0,0 +1,68 @@
+package snippet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Fetch renders a request as JavaScript with fetch.
+type Fetch struct{}
+
+func (Fetch) Name() string { return "JavaScript" }
+func (Fetch) Lang() string { return "javascript" }
+
+func (Fetch) Generate(r *Request) string {
+	w := bytes.NewBuffer([]byte{})
+	if r.IsMultipart() {
+		fmt.Fprintf(w, "const form = new FormData();\n")
+		for _, p := range r.Form {
+			fmt.Fprintf(w, "form.append(%v, %v);\n", jsString(p.Name), jsString(p.Value))
+		}
+		fmt.Fprintf(w, "\n")
+	}
+
+	fmt.Fprintf(w, "const res = await fetch(%v, {\n", jsString(r.FullURL()))
+	fmt.Fprintf(w, "  method: %v,\n", jsString(r.Method))
+	headers := append([]Param{}, r.Header...)
+	if r.JSON != "" {
+		headers = append(headers, Param{Name: "Content-Type", Value: r.EncType})
+	}
+	if len(headers) > 0 {
+		fmt.Fprintf(w, "  headers: {\n")
+		for _, h := range headers {
+			fmt.Fprintf(w, "    %v: %v,\n", jsString(h.Name), jsString(h.Value))
+		}
+		fmt.Fprintf(w, "  },\n")
+	}
+	switch {
+	case r.IsMultipart():
+		fmt.Fprintf(w, "  body: form,\n")
+	case r.IsForm():
+		fmt.Fprintf(w, "  body: new URLSearchParams({\n")
+		for _, p := range r.Form {
+			fmt.Fprintf(w, "    %v: %v,\n", jsString(p.Name), jsString(p.Value))
+		}
+		fmt.Fprintf(w, "  }),\n")
+	case r.JSON != "":
+		fmt.Fprintf(w, "  body: JSON.stringify(%v),\n", indent(r.JSON, "  "))
+	}
+	fmt.Fprintf(w, "});\n")
+	fmt.Fprintf(w, "const data = await res.json();")
+	return w.String()
+}
+
+// jsString returns s as a string literal of JavaScript.
+func jsString(s string) string {
+	w := bytes.NewBuffer([]byte{})
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(w.String(), "\n")
+}
+
+// indent prepends prefix to the lines of s except the first line.
+func indent(s, prefix string) string {
+	return strings.Replace(s, "\n", "\n"+prefix, -1)
+}
@@ -0,0 +1,64 @@
+package snippet
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// GoNetHTTP renders a request as Go with net/http.
+type GoNetHTTP struct{}
+
+func (GoNetHTTP) Name() string { return "Go" }
+func (GoNetHTTP) Lang() string { return "go" }
+
+func (GoNetHTTP) Generate(r *Request) string {
+	w := bytes.NewBuffer([]byte{})
+	body := "nil"
+	contentType := ""
+	switch {
+	case r.IsMultipart():
+		fmt.Fprintf(w, "body := &bytes.Buffer{}\n")
+		fmt.Fprintf(w, "form := multipart.NewWriter(body)\n")
+		for _, p := range r.Form {
+			fmt.Fprintf(w, "form.WriteField(%v, %v)\n", strconv.Quote(p.Name), strconv.Quote(p.Value))
+		}
+		fmt.Fprintf(w, "form.Close()\n\n")
+		body = "body"
+		contentType = "form.FormDataContentType()"
+	case r.IsForm():
+		fmt.Fprintf(w, "body := strings.NewReader(url.Values{\n")
+		for _, p := range r.Form {
+			fmt.Fprintf(w, "\t%v: {%v},\n", strconv.Quote(p.Name), strconv.Quote(p.Value))
+		}
+		fmt.Fprintf(w, "}.Encode())\n\n")
+		body = "body"
+		contentType = strconv.Quote(r.EncType)
+	case r.JSON != "":
+		fmt.Fprintf(w, "body := strings.NewReader(%v)\n\n", goRawString(r.JSON))
+		body = "body"
+		contentType = strconv.Quote(r.EncType)
+	}
+
+	fmt.Fprintf(w, "req, err := http.NewRequest(%v, %v, %v)\n", strconv.Quote(r.Method), strconv.Quote(r.FullURL()), body)
+	fmt.Fprintf(w, "if err != nil {\n\tlog.Fatal(err)\n}\n")
+	for _, h := range r.Header {
+		fmt.Fprintf(w, "req.Header.Set(%v, %v)\n", strconv.Quote(h.Name), strconv.Quote(h.Value))
+	}
+	if contentType != "" {
+		fmt.Fprintf(w, "req.Header.Set(\"Content-Type\", %v)\n", contentType)
+	}
+	fmt.Fprintf(w, "\nres, err := http.DefaultClient.Do(req)\n")
+	fmt.Fprintf(w, "if err != nil {\n\tlog.Fatal(err)\n}\n")
+	fmt.Fprintf(w, "defer res.Body.Close()")
+	return w.String()
+}
+
+// goRawString returns s as a raw string literal if possible.
+func goRawString(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,37 @@
+package snippet
+
+import "strings"
+
+// HTTPie renders a request as a command of HTTPie.
+type HTTPie struct{}
+
+func (HTTPie) Name() string { return "HTTPie" }
+func (HTTPie) Lang() string { return "bash" }
+
+func (HTTPie) Generate(r *Request) string {
+	cmd := "http"
+	switch {
+	case r.IsMultipart():
+		cmd += " --multipart"
+	case r.IsForm():
+		cmd += " --form"
+	}
+	args := []string{cmd + " " + r.Method + " " + ShellQuote(r.URL)}
+	for _, h := range r.Header {
+		args = append(args, ShellQuote(h.Name+":"+h.Value))
+	}
+	if r.JSON != "" && r.EncType != "application/json" {
+		args = append(args, ShellQuote("Content-Type:"+r.EncType))
+	}
+	for _, p := range r.Query {
+		args = append(args, ShellQuote(p.Name+"=="+p.Value))
+	}
+	for _, p := range r.Form {
+		args = append(args, ShellQuote(p.Name+"="+p.Value))
+	}
+	cmd = strings.Join(args, " \\\n    ")
+	if r.JSON != "" {
+		cmd += " <<< " + ShellQuote(r.JSON)
+	}
+	return cmd
+}
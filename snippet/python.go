@@ -0,0 +1,114 @@
+package snippet
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// PythonRequests renders a request as Python with requests.
+type PythonRequests struct{}
+
+func (PythonRequests) Name() string { return "Python" }
+func (PythonRequests) Lang() string { return "python" }
+
+func (PythonRequests) Generate(r *Request) string {
+	w := bytes.NewBuffer([]byte{})
+	fmt.Fprintf(w, "import requests\n\n")
+	switch r.Method {
+	case "GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS":
+		fmt.Fprintf(w, "res = requests.%v(\n", strings.ToLower(r.Method))
+	default:
+		fmt.Fprintf(w, "res = requests.request(\n    %v,\n", jsString(r.Method))
+	}
+	fmt.Fprintf(w, "    %v,\n", jsString(r.URL))
+
+	headers := append([]Param{}, r.Header...)
+	if r.JSON != "" && r.EncType != "application/json" {
+		headers = append(headers, Param{Name: "Content-Type", Value: r.EncType})
+	}
+	writePythonParams(w, "headers", headers, false)
+	writePythonParams(w, "params", r.Query, false)
+	if r.IsMultipart() {
+		writePythonParams(w, "files", r.Form, true)
+	} else {
+		writePythonParams(w, "data", r.Form, false)
+	}
+	switch {
+	case r.JSON == "":
+	case r.EncType == "application/json":
+		var v interface{}
+		dec := json.NewDecoder(strings.NewReader(r.JSON))
+		dec.UseNumber()
+		if err := dec.Decode(&v); err == nil {
+			fmt.Fprintf(w, "    json=%v,\n", pythonLiteral(v, "    "))
+			break
+		}
+		fallthrough
+	default:
+		fmt.Fprintf(w, "    data=%v,\n", jsString(r.JSON))
+	}
+	fmt.Fprintf(w, ")\n")
+	fmt.Fprintf(w, "print(res.json())")
+	return w.String()
+}
+
+// writePythonParams writes params as a keyword argument named name. Each
+// value is a tuple of a multipart field if multipart is true.
+func writePythonParams(w *bytes.Buffer, name string, params []Param, multipart bool) {
+	if len(params) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "    %v={\n", name)
+	for _, p := range params {
+		if multipart {
+			fmt.Fprintf(w, "        %v: (None, %v),\n", jsString(p.Name), jsString(p.Value))
+		} else {
+			fmt.Fprintf(w, "        %v: %v,\n", jsString(p.Name), jsString(p.Value))
+		}
+	}
+	fmt.Fprintf(w, "    },\n")
+}
+
+// pythonLiteral returns v decoded from JSON as a Python literal.
+func pythonLiteral(v interface{}, prefix string) string {
+	switch v := v.(type) {
+	case nil:
+		return "None"
+	case bool:
+		if v {
+			return "True"
+		}
+		return "False"
+	case json.Number:
+		return v.String()
+	case string:
+		return jsString(v)
+	case []interface{}:
+		if len(v) == 0 {
+			return "[]"
+		}
+		items := []string{}
+		for _, item := range v {
+			items = append(items, prefix+"    "+pythonLiteral(item, prefix+"    ")+",\n")
+		}
+		return "[\n" + strings.Join(items, "") + prefix + "]"
+	case map[string]interface{}:
+		if len(v) == 0 {
+			return "{}"
+		}
+		keys := []string{}
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		items := []string{}
+		for _, k := range keys {
+			items = append(items, prefix+"    "+jsString(k)+": "+pythonLiteral(v[k], prefix+"    ")+",\n")
+		}
+		return "{\n" + strings.Join(items, "") + prefix + "}"
+	}
+	return fmt.Sprintf("%v", v)
+}
@@ -0,0 +1,206 @@
+// Package snippet renders the request of a link as code snippets such as
+// curl or Python requests.
+package snippet
+
+import (
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+// Request is a request of a link filled with example values.
+type Request struct {
+	Method string
+	// URL is the base url and the href, without the query.
+	URL    string
+	Header []Param
+	// EncType is the type of the body, or empty if the request has no body.
+	EncType string
+	// Query is the query of the url.
+	Query []Param
+	// Form is the body of a form encType.
+	Form []Param
+	// JSON is the body of other encTypes.
+	JSON string
+}
+
+// Param is a name and value pair of a header, query or form.
+type Param struct {
+	Name  string
+	Value string
+}
+
+// FullURL returns the url with the query.
+func (r *Request) FullURL() string {
+	if len(r.Query) == 0 {
+		return r.URL
+	}
+	return r.URL + "?" + r.RawQuery()
+}
+
+// RawQuery returns the encoded query.
+func (r *Request) RawQuery() string {
+	q := []string{}
+	for _, p := range r.Query {
+		q = append(q, url.QueryEscape(p.Name)+"="+url.QueryEscape(p.Value))
+	}
+	return strings.Join(q, "&")
+}
+
+// IsForm returns true if the body is a form.
+func (r *Request) IsForm() bool {
+	return r.EncType == "application/x-www-form-urlencoded" || r.IsMultipart()
+}
+
+// IsMultipart returns true if the body is a multipart form.
+func (r *Request) IsMultipart() bool {
+	return r.EncType == "multipart/form-data"
+}
+
+// NewRequest returns the request of link l. headers are sent with every
+// request, such as "Authorization: Bearer xxx", and the request headers of
+// l are added to them.
+func NewRequest(l *schema.LinkDescription, baseURL string, headers []string) *Request {
+	r := &Request{
+		Method: strings.ToUpper(l.Method),
+		URL:    baseURL + l.Href,
+		Header: []Param{},
+	}
+	if r.Method == "" {
+		r.Method = "GET"
+	}
+	for _, h := range headers {
+		if n := strings.Index(h, ":"); n > 0 {
+			r.setHeader(strings.TrimSpace(h[0:n]), strings.TrimSpace(h[n+1:]))
+		}
+	}
+	for _, h := range l.RequestHeaders {
+		r.setHeader(h.Name, h.Example)
+	}
+
+	switch r.Method {
+	case "GET", "HEAD", "OPTIONS":
+		if l.HasSchema() {
+			r.Query = exampleParams(l.Schema)
+		}
+		return r
+	case "DELETE":
+		// the resource is not the body of DELETE.
+		if !l.HasSchema() {
+			return r
+		}
+	}
+
+	r.EncType = l.EncType
+	if r.EncType == "" {
+		r.EncType = "application/json"
+	}
+	if r.IsForm() {
+		r.Form = exampleParams(l.Schema)
+	} else {
+		r.JSON = l.Schema.ExampleJSON()
+	}
+	return r
+}
+
+// Authenticate adds the placeholder credential of a to r.
+func (r *Request) Authenticate(a *schema.AuthScheme) {
+	name, value, query := a.Credential()
+	if name == "" {
+		return
+	}
+	if query {
+		r.Query = append(r.Query, Param{Name: name, Value: value})
+		return
+	}
+	r.setHeader(name, value)
+}
+
+// setHeader replaces the header of the name, or adds it.
+func (r *Request) setHeader(name, value string) {
+	for i, h := range r.Header {
+		if strings.EqualFold(h.Name, name) {
+			r.Header[i].Value = value
+			return
+		}
+	}
+	r.Header = append(r.Header, Param{Name: name, Value: value})
+}
+
+// exampleParams returns the example of s as params.
+func exampleParams(s *schema.Schema) []Param {
+	params := []Param{}
+	for _, kv := range s.ExampleGetData() {
+		if kv == "" {
+			continue
+		}
+		pair := strings.SplitN(kv, "=", 2)
+		p := Param{}
+		p.Name, _ = url.QueryUnescape(pair[0])
+		if len(pair) == 2 {
+			p.Value, _ = url.QueryUnescape(pair[1])
+		}
+		params = append(params, p)
+	}
+	return params
+}
+
+// Generator renders a request as a snippet of a language or a tool.
+type Generator interface {
+	// Name is the label of the snippet, such as "HTTPie".
+	Name() string
+	// Lang is the language of the snippet for syntax highlighting.
+	Lang() string
+	Generate(r *Request) string
+}
+
+var (
+	generatorsMu sync.RWMutex
+	generators   = []Generator{}
+)
+
+// Register adds g to the generators used by Render. Snippets are rendered
+// in the order of registration.
+func Register(g Generator) {
+	generatorsMu.Lock()
+	defer generatorsMu.Unlock()
+	generators = append(generators, g)
+}
+
+// Generators returns the registered generators.
+func Generators() []Generator {
+	generatorsMu.RLock()
+	defer generatorsMu.RUnlock()
+	return append([]Generator{}, generators...)
+}
+
+// Snippet is a rendered snippet.
+type Snippet struct {
+	Name string
+	Lang string
+	Code string
+}
+
+// Render renders r with every registered generator.
+func Render(r *Request) []Snippet {
+	snippets := []Snippet{}
+	for _, g := range Generators() {
+		snippets = append(snippets, Snippet{Name: g.Name(), Lang: g.Lang(), Code: g.Generate(r)})
+	}
+	return snippets
+}
+
+func init() {
+	Register(Curl{})
+	Register(HTTPie{})
+	Register(Fetch{})
+	Register(PythonRequests{})
+	Register(GoNetHTTP{})
+}
+
+// ShellQuote quotes s for a POSIX shell.
+func ShellQuote(s string) string {
+	return "'" + strings.Replace(s, "'", `'\''`, -1) + "'"
+}
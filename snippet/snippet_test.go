@@ -0,0 +1,196 @@
+package snippet
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/hiroosak/gendoc/schema"
+)
+
+func TestNewRequest(t *testing.T) {
+	user := `{
+  "id": "snippet-user",
+  "properties": {"name": {"type": "string", "example": "gopher"}},
+  "links": [
+    {"href": "/users", "method": "GET", "schema": {"properties": {"q": {"type": "string", "example": "go"}}}},
+    {"href": "/users", "method": "POST"},
+    {"href": "/users/{id}", "method": "DELETE"},
+    {"href": "/users", "method": "PUT", "encType": "application/x-www-form-urlencoded"},
+    {"href": "/users", "method": "POST", "requestHeaders": [
+      {"name": "Idempotency-Key", "example": "abc"},
+      {"name": "x-token", "example": "BBB"}
+    ]}
+  ]
+}`
+	s, err := schema.NewSchemaFromBytes([]byte(user), "", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := NewRequest(s.Links[0], "https://api.example.com", []string{"X-Token: AAA"})
+	if r.FullURL() != "https://api.example.com/users?q=go" {
+		t.Errorf("unexpected url %v", r.FullURL())
+	}
+	if !reflect.DeepEqual(r.Header, []Param{{Name: "X-Token", Value: "AAA"}}) {
+		t.Errorf("unexpected header %v", r.Header)
+	}
+	if r.EncType != "" {
+		t.Errorf("GET must not have a body")
+	}
+
+	r = NewRequest(s.Links[1], "", nil)
+	if r.EncType != "application/json" || r.JSON != "{\n  \"name\": \"gopher\"\n}" {
+		t.Errorf("unexpected body %v %v", r.EncType, r.JSON)
+	}
+
+	r = NewRequest(s.Links[2], "", nil)
+	if r.EncType != "" {
+		t.Errorf("DELETE without schema must not have a body")
+	}
+
+	r = NewRequest(s.Links[3], "", nil)
+	if !reflect.DeepEqual(r.Form, []Param{{Name: "name", Value: "gopher"}}) {
+		t.Errorf("unexpected form %v", r.Form)
+	}
+
+	r = NewRequest(s.Links[4], "", []string{"X-Token: AAA"})
+	if !reflect.DeepEqual(r.Header, []Param{{Name: "X-Token", Value: "BBB"}, {Name: "Idempotency-Key", Value: "abc"}}) {
+		t.Errorf("unexpected header %v", r.Header)
+	}
+}
+
+func TestGenerators(t *testing.T) {
+	r := &Request{
+		Method:  "POST",
+		URL:     "https://api.example.com/users",
+		Header:  []Param{{Name: "X-Token", Value: "AAA"}},
+		EncType: "application/json",
+		JSON:    "{\n  \"name\": \"it's\",\n  \"admin\": false\n}",
+	}
+	res := map[string][]string{
+		"curl": {
+			"curl -X POST 'https://api.example.com/users' \\\n       -H 'X-Token: AAA' \\\n       -H 'Content-Type: application/json' \\\n       -d '{",
+			`"name": "it'\''s"`,
+		},
+		"HTTPie": {
+			"http POST 'https://api.example.com/users' \\\n    'X-Token:AAA' <<< '{",
+			`"name": "it'\''s"`,
+		},
+		"JavaScript": {
+			`const res = await fetch("https://api.example.com/users", {`,
+			`    "Content-Type": "application/json",`,
+			"  body: JSON.stringify({\n    \"name\": \"it's\",",
+		},
+		"Python": {
+			"res = requests.post(\n    \"https://api.example.com/users\",",
+			"    json={\n        \"admin\": False,\n        \"name\": \"it's\",\n    },",
+		},
+		"Go": {
+			"body := strings.NewReader(`{",
+			`req, err := http.NewRequest("POST", "https://api.example.com/users", body)`,
+			`req.Header.Set("Content-Type", "application/json")`,
+		},
+	}
+	for _, s := range Render(r) {
+		for _, expected := range res[s.Name] {
+			if !strings.Contains(s.Code, expected) {
+				t.Errorf("%v: %q is not contained in\n%v", s.Name, expected, s.Code)
+			}
+		}
+		delete(res, s.Name)
+	}
+	for name := range res {
+		t.Errorf("%v is not rendered", name)
+	}
+}
+
+func TestGeneratorsForm(t *testing.T) {
+	r := &Request{
+		Method:  "POST",
+		URL:     "https://api.example.com/files",
+		Header:  []Param{},
+		EncType: "multipart/form-data",
+		Form:    []Param{{Name: "name", Value: "a.txt"}},
+	}
+	res := map[string]string{
+		"curl":       "--form-string 'name=a.txt'",
+		"HTTPie":     "http --multipart POST",
+		"JavaScript": `form.append("name", "a.txt");`,
+		"Python":     `"name": (None, "a.txt"),`,
+		"Go":         `form.WriteField("name", "a.txt")`,
+	}
+	for _, s := range Render(r) {
+		if !strings.Contains(s.Code, res[s.Name]) {
+			t.Errorf("%v: %q is not contained in\n%v", s.Name, res[s.Name], s.Code)
+		}
+	}
+}
+
+func TestCurl(t *testing.T) {
+	res := []struct {
+		r        Request
+		expected string
+	}{
+		{
+			Request{Method: "GET", URL: "http://localhost/users", Query: []Param{{Name: "q", Value: "a b"}}},
+			"curl 'http://localhost/users?q=a+b'",
+		},
+		{
+			Request{Method: "HEAD", URL: "http://localhost/users"},
+			"curl -I 'http://localhost/users'",
+		},
+		{
+			Request{Method: "OPTIONS", URL: "http://localhost/users"},
+			"curl -X OPTIONS 'http://localhost/users'",
+		},
+		{
+			Request{Method: "DELETE", URL: "http://localhost/users/1", Header: []Param{{Name: "X-Token", Value: "AAA"}}},
+			"curl -X DELETE 'http://localhost/users/1' \\\n       -H 'X-Token: AAA'",
+		},
+		{
+			Request{Method: "PUT", URL: "http://localhost/users", EncType: "application/x-www-form-urlencoded", Form: []Param{{Name: "name", Value: "a&b"}}},
+			"curl -X PUT 'http://localhost/users' \\\n       --data-urlencode 'name=a&b'",
+		},
+		{
+			Request{Method: "POST", URL: "http://localhost/files", EncType: "multipart/form-data", Form: []Param{{Name: "file", Value: "@a.txt"}}},
+			"curl -X POST 'http://localhost/files' \\\n       --form-string 'file=@a.txt'",
+		},
+		{
+			Request{Method: "PATCH", URL: "http://localhost/users", EncType: "application/merge-patch+json", JSON: `{"name":"it's"}`},
+			"curl -X PATCH 'http://localhost/users' \\\n       -H 'Content-Type: application/merge-patch+json' \\\n       -d '{\"name\":\"it'\\''s\"}'",
+		},
+	}
+	for _, c := range res {
+		if actual := (Curl{}).Generate(&c.r); actual != c.expected {
+			t.Errorf("expected\n%v\nbut\n%v", c.expected, actual)
+		}
+	}
+}
+
+func TestShellQuote(t *testing.T) {
+	if actual := ShellQuote("it's"); actual != `'it'\''s'` {
+		t.Errorf("unexpected %v", actual)
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	res := []struct {
+		scheme   schema.AuthScheme
+		header   []Param
+		rawQuery string
+	}{
+		{schema.AuthScheme{Type: "bearer"}, []Param{{Name: "Authorization", Value: "Bearer <TOKEN>"}}, ""},
+		{schema.AuthScheme{Type: "basic"}, []Param{{Name: "Authorization", Value: "Basic dXNlcm5hbWU6cGFzc3dvcmQ="}}, ""},
+		{schema.AuthScheme{Type: "apiKey", In: "header", Param: "X-Service-Token", Example: "AAA"}, []Param{{Name: "X-Service-Token", Value: "AAA"}}, ""},
+		{schema.AuthScheme{Type: "apiKey", In: "query", Param: "key"}, []Param{}, "key=%3CAPI_KEY%3E"},
+		{schema.AuthScheme{Type: "oauth2"}, []Param{{Name: "Authorization", Value: "Bearer <ACCESS_TOKEN>"}}, ""},
+	}
+	for _, c := range res {
+		r := &Request{Method: "GET", URL: "http://localhost/", Header: []Param{}}
+		r.Authenticate(&c.scheme)
+		if !reflect.DeepEqual(r.Header, c.header) || r.RawQuery() != c.rawQuery {
+			t.Errorf("%v: unexpected %v %v", c.scheme.Type, r.Header, r.RawQuery())
+		}
+	}
+}
@@ -0,0 +1,43 @@
+{{ define "authentication" }}
+{{ with .Meta.AuthSchemes }}
+<h1 class="page-header">Authentication <a name="authentication" class="anchorjs-link" href="#authentication"><small><span class="glyphicon glyphicon-link xx-small" aria-hidden="true"></span></small></a></h1>
+{{ range . }}
+<h2>{{ .Name }}</h2>
+<p>{{ .Description }}</p>
+<dl class="dl-horizontal">
+  <dt>Type</dt><dd>{{ .Type }}</dd>
+  {{ if eq .Type "apiKey" }}<dt>{{ if eq .In "query" }}Query{{ else }}Header{{ end }}</dt><dd><code>{{ .Param }}</code></dd>{{ end }}
+  {{ if .AuthorizationURL }}<dt>Authorization URL</dt><dd><code>{{ .AuthorizationURL }}</code></dd>{{ end }}
+  {{ if .TokenURL }}<dt>Token URL</dt><dd><code>{{ .TokenURL }}</code></dd>{{ end }}
+</dl>
+{{ if .Scopes }}
+<div class="table-responsive">
+  <table class="table table-striped">
+    <thead>
+      <tr>
+        <th>Scope</th>
+        <th>Description</th>
+      </tr>
+    </thead>
+    <tbody>
+      {{ range $scope, $desc := .Scopes }}
+      <tr>
+        <td><code>{{ $scope }}</code></td>
+        <td>{{ $desc }}</td>
+      </tr>
+      {{ end }}
+    </tbody>
+  </table>
+</div>
+{{ end }}
+{{ end }}
+{{ end }}
+{{ end }}
+
+{{ define "link_auth" }}
+{{ with auth . }}
+<p><strong>Authentication:</strong>
+{{ range $i, $a := . }}{{ if $i }} or {{ end }}<a href="{{ page "" }}#authentication">{{ $a.Scheme.Name }}</a>{{ if $a.Scopes }} (scopes: {{ range $j, $s := $a.Scopes }}{{ if $j }}, {{ end }}<code>{{ $s }}</code>{{ end }}){{ end }}{{ end }}
+</p>
+{{ end }}
+{{ end }}
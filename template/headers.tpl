@@ -0,0 +1,24 @@
+{{ define "headers" }}
+<div class="table-responsive">
+  <table class="table table-striped">
+    <thead>
+      <tr>
+        <th>Name</th>
+        <th>Required</th>
+        <th>Description</th>
+        <th>Example</th>
+      </tr>
+    </thead>
+    <tbody>
+      {{ range . }}
+      <tr>
+        <td>{{ .Name }}</td>
+        <td>{{ if .Required }}required{{ end }}</td>
+        <td>{{ .Description }}</td>
+        <td><code>{{ .Example }}</code></td>
+      </tr>
+      {{ end }}
+    </tbody>
+  </table>
+</div>
+{{ end }}
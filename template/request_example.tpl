@@ -0,0 +1,22 @@
+{{ define "request_example" }}
+
+<h3>Request Example</h3>
+
+{{ $id := linkID . }}
+{{ $snippets := envSnippets . }}
+<ul class="nav nav-tabs" role="tablist">
+  {{ range $i, $s := $snippets }}
+  <li role="presentation"{{ if eq $i 0 }} class="active"{{ end }}><a href="#{{ $id }}-{{ $i }}" role="tab" data-toggle="tab">{{ $s.Name }}</a></li>
+  {{ end }}
+</ul>
+<div class="tab-content">
+  {{ range $i, $s := $snippets }}
+  <div role="tabpanel" class="tab-pane{{ if eq $i 0 }} active{{ end }}" id="{{ $id }}-{{ $i }}">
+    {{ range $s.Examples }}
+<pre class="env-example" data-env="{{ .Env }}"{{ if not .Default }} style="display: none"{{ end }}><code class="{{ $s.Lang }}">{{ .Code }}</code></pre>
+    {{ end }}
+  </div>
+  {{ end }}
+</div>
+
+{{ end }}
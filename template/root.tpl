@@ -53,6 +53,12 @@
       color: #fff;
       background-color: #428bca;
     }
+    .sidebar-group {
+      padding: 10px 20px 5px;
+      color: #777;
+      font-size: 12px;
+      text-transform: uppercase;
+    }
     .nav-sidebar-submenu {
       margin-right: 0px
       margin-bottom: 0px;
@@ -107,7 +113,16 @@
     </div>
 
     <div class="col-sm-8 col-sm-offset-4 col-md-9 col-md-offset-3 main">
+      {{ if .Index }}
       {{ .Overview }}
+      {{ range .Pages }}
+      <div id="{{ .ID }}">
+        {{ .Body }}
+      </div>
+      {{ end }}
+      {{ .Changelog }}
+      {{ template "authentication" . }}
+      {{ end }}
       {{ template "schema" . }}
     </div>
 
@@ -115,6 +130,39 @@
     <script src="https://maxcdn.bootstrapcdn.com/bootstrap/3.3.4/js/bootstrap.min.js"></script>
     <script src="https://cdnjs.cloudflare.com/ajax/libs/highlight.js/8.6/highlight.min.js"></script>
     <script>hljs.initHighlightingOnLoad();</script>
+    <script>
+    {{ if .SearchIndexURL }}
+    var searchIndex = [];
+    $.getJSON({{ .SearchIndexURL }}, function(index) { searchIndex = index; });
+    {{ else }}
+    var searchIndex = {{ .SearchIndex }};
+    {{ end }}
+    $('#search').on('input', function() {
+      var terms = $(this).val().toLowerCase().split(/\s+/).filter(function(t) { return t !== ''; });
+      var results = $('#search-results').empty();
+      if (terms.length === 0) {
+        return;
+      }
+      var found = searchIndex.filter(function(e) {
+        var text = (e.title + ' ' + (e.description || '')).toLowerCase();
+        return terms.every(function(t) { return text.indexOf(t) >= 0; });
+      });
+      $.each(found.slice(0, 20), function(i, e) {
+        var a = $('<a>').attr('href', e.href).text(e.title);
+        a.prepend($('<span class="label label-default">').text(e.kind), ' ');
+        results.append($('<li>').append(a));
+      });
+      if (found.length === 0) {
+        results.append($('<li>').append($('<a>').text('No results')));
+      }
+    });
+
+    $('#env-switcher').change(function() {
+      var env = $(this).val();
+      $('.env-example').hide();
+      $('.env-example').filter(function() { return $(this).data('env') === env; }).show();
+    });
+    </script>
   </body>
 </html>
 {{end}}
@@ -1,9 +1,12 @@
 {{ define "schema" }}
-  {{ range .SchemaSlice }}
+  {{ range .Content }}
+  {{ if .Name }}<h4 class="text-muted text-uppercase">{{ .Name }}</h4>{{ end }}
+  {{ range .Resources }}
+    {{ $r := . }}
     <h1 class="page-header">
       {{ .Id }} <a name="{{ .Id }}" class="anchorjs-link" href="#{{ .Id }}"> <small><span class="glyphicon glyphicon-link xx-small" aria-hidden="true"></span></small></a> 
     </h1>
-    <p>{{ .Description }}</p>
+    {{ markdown .Description }}
     <h2>Attributes</h2>
     <div class="table-responsive">
       <table class="table table-striped">
@@ -18,13 +21,14 @@
           </tr>
         </thead>
         <tbody>
-          {{ range $n, $d := .Properties }}
-          <tr>
+          {{ range $n := .PropertyNames }}
+          {{ $d := index $r.Properties $n }}
+          <tr id="{{ $r.Id }}-{{ $n }}">
             <td></td>
             <td>{{ $n }}</td>
             <td>{{ range $i, $type := $d.ResolveType }}{{$type}}<br/>{{end}}</td>
             <td>{{ $d.ResolveFormat }}</td>
-            <td>{{ $d.ResolveDescription }}</td>
+            <td>{{ markdown $d.ResolveDescription }}</td>
             <td>
               <code>{{ $d.ExampleJSON }}</code>
             </td>
@@ -38,11 +42,19 @@
         {{ .Method }} {{ .Href }} - {{ .Title }} <a name="{{ .Method }}-{{ .Href }}" class="anchorjs-link" href="#{{ .Method }}-{{ .Href }}"><small><span class="glyphicon glyphicon-link" aria-hidden="true"></span></small></a> 
       </h2>
 
-      <p>{{ .Description }}</p>
+      {{ markdown .Description }}
+
+      {{ template "link_auth" . }}
 
-      {{ template "curl_example" . }}
+      {{ if .RequestHeaders }}
+      <h3>Request Headers</h3>
+      {{ template "headers" .RequestHeaders }}
+      {{ end }}
+
+      {{ template "request_example" . }}
       {{ template "response_example" . }}
 
     {{ end }}
   {{ end }}
+  {{ end }}
 {{ end }}
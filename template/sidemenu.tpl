@@ -1,12 +1,33 @@
 {{ define "sidemenu" }}
 <ul class="nav nav-sidebar">
-  <li><a href="#"><strong>{{ .Meta.Title }}</strong></a></li>
-{{ range .SchemaSlice }}
-  <li><a href="#{{ .Id }}">{{ .Id }}</a></li>
+  <li><a href="{{ page "" }}#"><strong>{{ .Meta.Title }}</strong></a></li>
+  <li>
+    <input id="search" type="search" class="form-control" placeholder="Search" autocomplete="off">
+    <ul id="search-results" class="nav nav-sidebar-submenu"></ul>
+  </li>
+{{ with environments }}
+  <li>
+    <select id="env-switcher" class="form-control">
+      {{ range . }}
+      <option value="{{ .Name }}"{{ if eq .Name $.Meta.Env }} selected{{ end }}>{{ .Name }} - {{ .BaseURL }}</option>
+      {{ end }}
+    </select>
+  </li>
+{{ end }}
+{{ range .Pages }}
+  <li><a href="{{ page "" }}#{{ .ID }}">{{ .Title }}</a></li>
+{{ end }}
+{{ range .Groups }}
+{{ if .Name }}
+  <li class="sidebar-group">{{ .Name }}</li>
+{{ end }}
+{{ range .Resources }}
+{{ $page := page .Id }}
+  <li><a href="{{ $page }}#{{ .Id }}">{{ .Id }}</a></li>
   <li>
     <ul class="nav nav-sidebar-submenu">
       {{ range .Links }}
-        <li><a href="#{{ .Method }}-{{ .Href }}">
+        <li><a href="{{ $page }}#{{ .Method }}-{{ .Href }}">
             {{ if eq .Rel "notImplemented" }}
             <span class="label label-warning">Not Implemented</span>
             {{ end }}
@@ -17,5 +38,6 @@
     </ul>
   </li>
 {{ end }}
+{{ end }}
 </ul>
 {{ end }}